@@ -2,9 +2,23 @@ package cmd
 
 import (
 	"fmt"
+	"log"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/wardle/go-terminology/terminology"
+	"golang.org/x/text/language"
+)
+
+var (
+	incremental      bool
+	indexHierarchies []string
+	indexRefsets     []string
+	indexLanguages   string
+	indexWorkers     int
+	indexPhonetic    bool
 )
 
 // dataCmd represents the data command
@@ -26,7 +40,9 @@ var importCmd = &cobra.Command{
 			if i == 0 {
 				continue //skip data-dir
 			}
-			sct.PerformImport(filename)
+			if err := sct.PerformImport(filename); err != nil {
+				return err
+			}
 		}
 		return nil
 	},
@@ -44,11 +60,31 @@ var exportCmd = &cobra.Command{
 var indexCmd = &cobra.Command{
 	Use:   "index <data-dir>",
 	Short: "Build search index from currently loaded data",
-	Long:  `Build the search index from currently loaded data.`,
+	Long: `Build the search index from currently loaded data.
+The index is built alongside the existing index, which is replaced only once the build is complete.
+The datastore is opened read-only while rebuilding, so a running server can continue to use it, and
+switches to the new index automatically.
+With --incremental, only concepts changed by imports since the index was last updated are indexed again,
+updating the current index, so the datastore must not be in use.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		err := sct.Index()
-		time.Sleep(60 * time.Second) // Dirty hack for Bleve Moss storage to ensure data is written to disk
-		return err
+		options, err := indexOptions()
+		if err != nil {
+			return err
+		}
+		if incremental {
+			return sct.IndexIncremental(options)
+		}
+		return sct.Index(options)
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		if err := rootCmd.PersistentPostRunE(cmd, args); err != nil || incremental {
+			return err
+		}
+		// the datastore was opened read-only to rebuild the index, so changed components are forgotten once it is closed
+		if err := terminology.ClearChanged(args[0]); err != nil {
+			log.Printf("search index rebuilt, but changed components will be indexed again by the next incremental update: %v", err)
+		}
+		return nil
 	},
 }
 
@@ -86,7 +122,103 @@ var infoCmd = &cobra.Command{
 	},
 }
 
+var backupCmd = &cobra.Command{
+	Use:   "backup <data-dir> <archive>",
+	Short: "Backup datastore and search index to a compressed archive",
+	Long: `Backup datastore and search index to a compressed archive.
+Consistent copies of the datastore and search index are taken, so backup is safe while the datastore is in use.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("must specify data-dir and archive")
+		}
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		if err := sct.Backup(f); err != nil {
+			f.Close()
+			os.Remove(args[1])
+			return err
+		}
+		return f.Close()
+	},
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <data-dir> <archive>",
+	Short: "Restore datastore and search index from a backup archive",
+	Long: `Restore datastore and search index from a backup archive.
+The archive checksums are verified before any existing data in data-dir is replaced.
+The search index is restored into data-dir. The datastore must not be in use.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 2 {
+			return fmt.Errorf("must specify data-dir and archive")
+		}
+		f, err := os.Open(args[1])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return terminology.Restore(f, args[0])
+	},
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// override RootCmd version as the datastore must not be open
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		// override RootCmd version
+		return nil
+	},
+}
+
+// indexOptions returns options for indexing from the command-line flags
+func indexOptions() (terminology.IndexOptions, error) {
+	var err error
+	options := terminology.IndexOptions{
+		Workers:  indexWorkers,
+		Phonetic: indexPhonetic,
+		Progress: func(p terminology.IndexProgress) {
+			if p.Done {
+				fmt.Fprintf(os.Stderr, "\nProcessed total: %d descriptions from %d concepts in %s.\n", p.Descriptions, p.Concepts, p.Elapsed)
+			} else if p.Descriptions > 0 {
+				fmt.Fprintf(os.Stderr, "\rProcessed %d descriptions in %s. Mean time per description: %s...", p.Descriptions, p.Elapsed, p.Elapsed/time.Duration(p.Descriptions))
+			}
+		},
+	}
+	if options.Hierarchies, err = parseIdentifiers(indexHierarchies); err != nil {
+		return options, err
+	}
+	if options.ReferenceSets, err = parseIdentifiers(indexRefsets); err != nil {
+		return options, err
+	}
+	if indexLanguages != "" {
+		if options.Languages, _, err = language.ParseAcceptLanguage(indexLanguages); err != nil {
+			return options, err
+		}
+	}
+	return options, nil
+}
+
+// parseIdentifiers parses a list of SNOMED-CT identifiers
+func parseIdentifiers(ss []string) ([]int64, error) {
+	result := make([]int64, 0, len(ss))
+	for _, s := range ss {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid identifier: %s", s)
+		}
+		result = append(result, id)
+	}
+	return result, nil
+}
+
 func init() {
 	rootCmd.AddCommand(dataCmd)
-	dataCmd.AddCommand(importCmd, exportCmd, indexCmd, precomputeCmd, resetCmd, infoCmd)
+	dataCmd.AddCommand(importCmd, exportCmd, indexCmd, precomputeCmd, resetCmd, infoCmd, backupCmd, restoreCmd)
+	indexCmd.Flags().BoolVar(&incremental, "incremental", false, "only index concepts changed since the index was last updated")
+	indexCmd.Flags().StringSliceVar(&indexHierarchies, "hierarchy", nil, "only index concepts within the hierarchies specified by `id`")
+	indexCmd.Flags().StringSliceVar(&indexRefsets, "refset", nil, "only index concepts that are members of the reference sets specified by `id`")
+	indexCmd.Flags().StringVar(&indexLanguages, "languages", "", "only index descriptions in the `languages` specified, e.g. \"en,fr\"")
+	indexCmd.Flags().IntVar(&indexWorkers, "workers", 0, "number of concurrent workers (default number of CPUs)")
+	indexCmd.Flags().BoolVar(&indexPhonetic, "phonetic", false, "include a phonetic encoding of each term, for searches that match words that sound alike")
 }
@@ -8,17 +8,24 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
 	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/wardle/go-terminology/terminology"
 )
 
 var compactIndexCmd = &cobra.Command{
 	Use:   "compact <data-dir>",
 	Short: "Manually compact Bleve leveldb search index files",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		path := filepath.Join(args[0], "bleve_index", "store")
+		indexPath := filepath.Join(args[0], "bleve_index")
 		// Overide index path if --index set to alternate directory
 		if index != "" {
-			path = filepath.Join(index, "store")
+			indexPath = index
 		}
+		// Use the most recently rebuilt index, if any
+		indexPath, err := terminology.IndexPath(args[0], indexPath)
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(indexPath, "store")
 
 		fmt.Printf("%+v\n", path)
 		options := opt.Options{CompactionTableSizeMultiplier: 2}
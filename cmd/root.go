@@ -6,12 +6,14 @@ import (
 	"os"
 	"runtime/pprof"
 
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/spf13/cobra"
+	"github.com/wardle/go-terminology/snomed"
 	"github.com/wardle/go-terminology/terminology"
 )
 
 var sct *terminology.Svc
-var profilecpu, index, version, build string
+var profilecpu, index, expansions, ranking, version, build string
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -36,6 +38,10 @@ var rootCmd = &cobra.Command{
 		options := terminology.Options{
 			Index:         args[0],
 			IndexReadOnly: true,
+			Expansions:    expansions,
+		}
+		if options.Ranking, err = loadRanking(ranking); err != nil {
+			return fmt.Errorf("couldn't load ranking weights: %v", err)
 		}
 		// Overide options for index path it --index set to alternate directory
 		if index != "" {
@@ -46,10 +52,12 @@ var rootCmd = &cobra.Command{
 		if _, ok := readWriteCommands[cmd.CalledAs()]; ok {
 			readOnly = false
 		}
-		// Special case for index command
+		// Special case for index command. A rebuilt index is built in a new directory, so that the datastore and
+		// current index are opened read-only and the index can be rebuilt while a server is using them, but an
+		// incremental update changes the current index and clears the record of changed components in the datastore.
 		if cmd.CalledAs() == "index" {
-			readOnly = true
-			options.IndexReadOnly = false
+			readOnly = !incremental
+			options.IndexReadOnly = !incremental
 		}
 
 		// Create new terminology service
@@ -68,6 +76,23 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// loadRanking reads weights for ranking search results from the JSON file specified, if any
+func loadRanking(path string) (*snomed.SearchRequest_Ranking, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	result := new(snomed.SearchRequest_Ranking)
+	if err := jsonpb.Unmarshal(f, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 // Receives version and build strings from main
@@ -83,4 +108,6 @@ func Execute(version string, build string) {
 func init() {
 	rootCmd.PersistentFlags().StringVar(&profilecpu, "profile-cpu", "", "write cpu profile to `file` specified")
 	rootCmd.PersistentFlags().StringVar(&index, "index", "", "use specified `directory` for search index instead of defaulting to <data-dir>")
+	rootCmd.PersistentFlags().StringVar(&expansions, "expansions", "", "use query expansion dictionary in `file` specified instead of defaulting to <data-dir>/expansions.txt")
+	rootCmd.PersistentFlags().StringVar(&ranking, "ranking", "", "use weights for ranking search results from JSON `file` specified instead of the defaults")
 }
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wardle/go-terminology/snomed"
+)
+
+var (
+	searchExplain     bool
+	searchMaximumHits int
+	searchConstraint  string
+	searchLanguages   string
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <data-dir> <text>",
+	Short: "Search for descriptions matching the text specified",
+	Long: `Search for descriptions matching the text specified, printing the concept, description, score and term of each result.
+With --ecl, results are limited to concepts satisfying an expression constraint, such as "<< 71388002".
+With --explain, the analysed words of the search, the query and how each result was scored are also printed,
+to help understand why a description is, or is not, found.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) < 2 {
+			return fmt.Errorf("must specify text to search")
+		}
+		request := &snomed.SearchRequest{
+			Search:            strings.Join(args[1:], " "),
+			MaximumHits:       int32(searchMaximumHits),
+			Explain:           searchExplain,
+			Ecl:               searchConstraint,
+			AcceptedLanguages: searchLanguages,
+		}
+		result, err := sct.SearchHits(context.Background(), request)
+		if err != nil {
+			return err
+		}
+		if result.Explanation != nil {
+			fmt.Printf("Tokens: %s\nQuery: %s\n\n", strings.Join(result.Explanation.Tokens, ", "), result.Explanation.Query)
+		}
+		for _, hit := range result.Hits {
+			description, err := sct.GetDescription(hit.DescriptionID)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%d\t%d\t%.4f\t%s\n", description.ConceptId, description.Id, hit.Score, description.Term)
+			if hit.Explanation != "" {
+				fmt.Printf("%s\n\n", hit.Explanation)
+			}
+		}
+		fmt.Printf("%d of %d matching descriptions\n", len(result.Hits), result.Total)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVar(&searchExplain, "explain", false, "explain how the search was executed and how each result was scored")
+	searchCmd.Flags().IntVar(&searchMaximumHits, "max", 20, "maximum number of results")
+	searchCmd.Flags().StringVar(&searchConstraint, "ecl", "", "limit results to concepts satisfying this expression constraint")
+	searchCmd.Flags().StringVar(&searchLanguages, "languages", "", "limit results to descriptions in the `languages` specified, e.g. \"en-GB,en\"")
+}
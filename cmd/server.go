@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/wardle/go-terminology/server"
@@ -19,6 +21,7 @@ var serverCmd = &cobra.Command{
 	Short: "Runs the terminology server",
 	Long:  `The server command runs the terminology server.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		go sct.WatchIndex(context.Background(), 30*time.Second) // switch to a rebuilt search index without restarting
 		server.Serve(sct, address+":"+strconv.Itoa(port))
 		return nil
 	},
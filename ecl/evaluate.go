@@ -0,0 +1,477 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package ecl
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/storage"
+)
+
+// Evaluator evaluates expression constraints against a store, such as a terminology service
+type Evaluator struct {
+	store storage.Store
+}
+
+// New creates an evaluator for expression constraints using the store specified
+func New(store storage.Store) *Evaluator {
+	return &Evaluator{store: store}
+}
+
+// Evaluate parses and evaluates the expression constraint, returning the identifiers of the
+// matching concepts in ascending order. Parse errors are returned as is, so that they can be
+// distinguished by callers using Parse first, if required.
+func (ev *Evaluator) Evaluate(ctx context.Context, ecl string) ([]int64, error) {
+	c, err := Parse(ecl)
+	if err != nil {
+		return nil, err
+	}
+	return ev.EvaluateConstraint(ctx, c)
+}
+
+// EvaluateConstraint evaluates a parsed expression constraint, returning the identifiers of the
+// matching concepts in ascending order
+func (ev *Evaluator) EvaluateConstraint(ctx context.Context, c *Constraint) ([]int64, error) {
+	set, err := ev.Concepts(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int64, 0, len(set))
+	for id := range set {
+		result = append(result, id)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result, nil
+}
+
+// Concepts evaluates a parsed expression constraint, returning the set of matching concepts
+func (ev *Evaluator) Concepts(ctx context.Context, c *Constraint) (map[int64]bool, error) {
+	e := &evaluation{
+		ctx:   ctx,
+		store: ev.store,
+		sets:  make(map[expression]map[int64]bool),
+	}
+	return e.evaluate(c.root)
+}
+
+// evaluation holds the state of a single evaluation, caching the concepts selected by each
+// expression, such as attribute names and values, used repeatedly during refinement
+type evaluation struct {
+	ctx   context.Context
+	store storage.Store
+	sets  map[expression]map[int64]bool
+	all   map[int64]bool // all active concepts, fetched on first use
+}
+
+func (e *evaluation) evaluate(expr expression) (map[int64]bool, error) {
+	if set, ok := e.sets[expr]; ok {
+		return set, nil
+	}
+	if err := e.ctx.Err(); err != nil {
+		return nil, err
+	}
+	var set map[int64]bool
+	var err error
+	switch x := expr.(type) {
+	case *subExpression:
+		set, err = e.evaluateSubExpression(x)
+	case *compound:
+		set, err = e.evaluateCompound(x)
+	case *refined:
+		set, err = e.evaluateRefined(x)
+	case *dotted:
+		set, err = e.evaluateDotted(x)
+	default:
+		err = fmt.Errorf("unsupported expression constraint %T", expr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.sets[expr] = set
+	return set, nil
+}
+
+// contains determines whether the concept is selected by the expression, avoiding the
+// evaluation of a wildcard, which would otherwise select all concepts
+func (e *evaluation) contains(expr expression, conceptID int64) (bool, error) {
+	if x, ok := expr.(*subExpression); ok && x.wildcard && !x.memberOf && x.operator == self {
+		return true, nil
+	}
+	set, err := e.evaluate(expr)
+	if err != nil {
+		return false, err
+	}
+	return set[conceptID], nil
+}
+
+func (e *evaluation) evaluateSubExpression(x *subExpression) (map[int64]bool, error) {
+	var focus map[int64]bool
+	var err error
+	switch {
+	case x.wildcard && x.memberOf:
+		focus, err = e.referenceSets()
+	case x.wildcard:
+		focus, err = e.allConcepts()
+	case x.nested != nil:
+		focus, err = e.evaluate(x.nested)
+	default:
+		focus = map[int64]bool{x.conceptID: true}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if x.memberOf {
+		if focus, err = e.members(focus); err != nil {
+			return nil, err
+		}
+	}
+	switch x.operator {
+	case self:
+		return focus, nil
+	case descendantOf, descendantOrSelfOf:
+		return e.closure(focus, e.children, true, x.operator == descendantOrSelfOf)
+	case childOf, childOrSelfOf:
+		return e.closure(focus, e.children, false, x.operator == childOrSelfOf)
+	case ancestorOf, ancestorOrSelfOf:
+		return e.closure(focus, e.parents, true, x.operator == ancestorOrSelfOf)
+	case parentOf, parentOrSelfOf:
+		return e.closure(focus, e.parents, false, x.operator == parentOrSelfOf)
+	}
+	return nil, fmt.Errorf("unsupported constraint operator %d", x.operator)
+}
+
+// closure returns the concepts related to those specified by following the function
+// specified, either recursively or for a single step, and optionally including the concepts themselves.
+func (e *evaluation) closure(focus map[int64]bool, next func(int64) ([]int64, error), recursive bool, includeSelf bool) (map[int64]bool, error) {
+	result := make(map[int64]bool)
+	pending := make([]int64, 0, len(focus))
+	for id := range focus {
+		if includeSelf {
+			result[id] = true
+		}
+		pending = append(pending, id)
+	}
+	visited := make(map[int64]bool, len(focus))
+	for len(pending) > 0 {
+		if err := e.ctx.Err(); err != nil {
+			return nil, err
+		}
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if visited[id] {
+			continue
+		}
+		visited[id] = true
+		related, err := next(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range related {
+			result[r] = true
+			if recursive {
+				pending = append(pending, r)
+			}
+		}
+	}
+	return result, nil
+}
+
+// children returns the direct children of the concept, using active IS-A relationships
+func (e *evaluation) children(conceptID int64) ([]int64, error) {
+	rels, err := e.store.GetChildRelationships(&snomed.Concept{Id: conceptID})
+	if err != nil {
+		return nil, err
+	}
+	var result []int64
+	for _, rel := range rels {
+		if rel.Active && rel.TypeId == snomed.IsA {
+			result = append(result, rel.SourceId)
+		}
+	}
+	return result, nil
+}
+
+// parents returns the direct parents of the concept, using active IS-A relationships
+func (e *evaluation) parents(conceptID int64) ([]int64, error) {
+	rels, err := e.store.GetParentRelationships(&snomed.Concept{Id: conceptID})
+	if err != nil {
+		return nil, err
+	}
+	var result []int64
+	for _, rel := range rels {
+		if rel.Active && rel.TypeId == snomed.IsA {
+			result = append(result, rel.DestinationId)
+		}
+	}
+	return result, nil
+}
+
+// members returns the components referenced by active items of the reference sets specified
+func (e *evaluation) members(refsets map[int64]bool) (map[int64]bool, error) {
+	result := make(map[int64]bool)
+	for refset := range refsets {
+		err := e.store.IterateReferenceSetItems(e.ctx, refset, storage.Filter{ActiveOnly: true}, func(item *snomed.ReferenceSetItem) error {
+			result[item.ReferencedComponentId] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// referenceSets returns the installed reference sets
+func (e *evaluation) referenceSets() (map[int64]bool, error) {
+	refsets, err := e.store.GetAllReferenceSets()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]bool, len(refsets))
+	for _, refset := range refsets {
+		result[refset] = true
+	}
+	return result, nil
+}
+
+// allConcepts returns all active concepts
+func (e *evaluation) allConcepts() (map[int64]bool, error) {
+	if e.all != nil {
+		return e.all, nil
+	}
+	all := make(map[int64]bool)
+	err := e.store.IterateContext(e.ctx, func(c *snomed.Concept) error {
+		if c.Active {
+			all[c.Id] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	e.all = all
+	return all, nil
+}
+
+func (e *evaluation) evaluateCompound(x *compound) (map[int64]bool, error) {
+	first, err := e.evaluate(x.operands[0])
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]bool, len(first))
+	for id := range first {
+		result[id] = true
+	}
+	for _, operand := range x.operands[1:] {
+		set, err := e.evaluate(operand)
+		if err != nil {
+			return nil, err
+		}
+		switch x.op {
+		case "AND":
+			for id := range result {
+				if !set[id] {
+					delete(result, id)
+				}
+			}
+		case "OR":
+			for id := range set {
+				result[id] = true
+			}
+		case "MINUS":
+			for id := range set {
+				delete(result, id)
+			}
+		}
+	}
+	return result, nil
+}
+
+// evaluateDotted returns the values of the attributes, in turn, of the concepts selected by the source
+func (e *evaluation) evaluateDotted(x *dotted) (map[int64]bool, error) {
+	current, err := e.evaluate(x.source)
+	if err != nil {
+		return nil, err
+	}
+	for _, attr := range x.attributes {
+		values := make(map[int64]bool)
+		for id := range current {
+			rels, err := e.store.GetParentRelationships(&snomed.Concept{Id: id})
+			if err != nil {
+				return nil, err
+			}
+			for _, rel := range rels {
+				if !rel.Active {
+					continue
+				}
+				ok, err := e.contains(attr, rel.TypeId)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					values[rel.DestinationId] = true
+				}
+			}
+		}
+		current = values
+	}
+	return current, nil
+}
+
+// evaluateRefined returns the concepts selected by the focus that satisfy the refinement
+func (e *evaluation) evaluateRefined(x *refined) (map[int64]bool, error) {
+	focus, err := e.evaluate(x.focus)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]bool)
+	for id := range focus {
+		if err := e.ctx.Err(); err != nil {
+			return nil, err
+		}
+		c := &concept{id: id}
+		if c.relationships, err = e.store.GetParentRelationships(&snomed.Concept{Id: id}); err != nil {
+			return nil, err
+		}
+		c.relationships = activeRelationships(c.relationships)
+		ok, err := e.satisfies(c, c.relationships, x.refinement)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result[id] = true
+		}
+	}
+	return result, nil
+}
+
+// concept is a concept being tested against a refinement, with its active relationships,
+// and those for which it is the destination, fetched only if a reverse attribute requires them.
+type concept struct {
+	id            int64
+	relationships []*snomed.Relationship
+	reverse       []*snomed.Relationship
+}
+
+func activeRelationships(rels []*snomed.Relationship) []*snomed.Relationship {
+	result := make([]*snomed.Relationship, 0, len(rels))
+	for _, rel := range rels {
+		if rel.Active {
+			result = append(result, rel)
+		}
+	}
+	return result
+}
+
+// satisfies determines whether the relationships specified, either all those of the concept
+// or those of a single relationship group, satisfy the refinement.
+func (e *evaluation) satisfies(c *concept, rels []*snomed.Relationship, r refinement) (bool, error) {
+	switch x := r.(type) {
+	case *refinementSet:
+		for _, item := range x.items {
+			ok, err := e.satisfies(c, rels, item)
+			if err != nil {
+				return false, err
+			}
+			if x.op == "OR" && ok {
+				return true, nil
+			}
+			if x.op == "AND" && !ok {
+				return false, nil
+			}
+		}
+		return x.op == "AND", nil
+	case *attribute:
+		return e.satisfiesAttribute(c, rels, x)
+	case *attributeGroup:
+		matched := 0
+		for _, group := range groups(rels) {
+			ok, err := e.satisfies(c, group, x.refinement)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				matched++
+			}
+		}
+		return x.cardinality.includes(matched), nil
+	}
+	return false, fmt.Errorf("unsupported refinement %T", r)
+}
+
+// satisfiesAttribute determines whether the number of relationships matching the attribute is
+// within its cardinality. For a reverse attribute, the relationships for which the concept is the
+// destination are used, irrespective of any attribute group.
+func (e *evaluation) satisfiesAttribute(c *concept, rels []*snomed.Relationship, x *attribute) (bool, error) {
+	if x.reverse {
+		if c.reverse == nil {
+			reverse, err := e.store.GetChildRelationships(&snomed.Concept{Id: c.id})
+			if err != nil {
+				return false, err
+			}
+			c.reverse = activeRelationships(reverse)
+		}
+		rels = c.reverse
+	}
+	matched := 0
+	for _, rel := range rels {
+		ok, err := e.contains(x.name, rel.TypeId)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+		value := rel.DestinationId
+		if x.reverse {
+			value = rel.SourceId
+		}
+		if ok, err = e.contains(x.value, value); err != nil {
+			return false, err
+		}
+		if ok == x.equal {
+			matched++
+		}
+	}
+	return x.cardinality.includes(matched), nil
+}
+
+// groups splits relationships into their relationship groups, with each relationship in group
+// zero, which is ungrouped, treated as a group of its own. IS-A relationships are not grouped.
+func groups(rels []*snomed.Relationship) [][]*snomed.Relationship {
+	var result [][]*snomed.Relationship
+	index := make(map[int64]int)
+	for _, rel := range rels {
+		if rel.TypeId == snomed.IsA {
+			continue
+		}
+		if rel.RelationshipGroup == 0 {
+			result = append(result, []*snomed.Relationship{rel})
+			continue
+		}
+		i, ok := index[rel.RelationshipGroup]
+		if !ok {
+			i = len(result)
+			index[rel.RelationshipGroup] = i
+			result = append(result, nil)
+		}
+		result[i] = append(result[i], rel)
+	}
+	return result
+}
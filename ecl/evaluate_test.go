@@ -0,0 +1,51 @@
+package ecl_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/wardle/go-terminology/ecl"
+	"github.com/wardle/go-terminology/terminology"
+	"github.com/wardle/go-terminology/terminology/terminologytest"
+)
+
+// TestEvaluate evaluates expression constraints against a tiny release
+func TestEvaluate(t *testing.T) {
+	svc := terminologytest.New(t, terminology.Options{InMemoryIndex: true})
+	defer svc.Close()
+	tests := []struct {
+		ecl      string
+		expected []int64
+	}{
+		{"24700007 |Multiple sclerosis|", []int64{24700007}},
+		{"< 64572001 |Disease|", []int64{6118003, 24700007}},
+		{"<< 64572001 |Disease|", []int64{6118003, 24700007, 64572001}},
+		{"<! 404684003 |Clinical finding|", []int64{64572001, 125605004}},
+		{">! 24700007", []int64{6118003}},
+		{">> 6118003", []int64{6118003, 64572001, 138875005, 404684003}},
+		{"^ 991411000000109", []int64{24700007, 125605004}},
+		{"< 404684003 AND ^ 991411000000109", []int64{24700007, 125605004}},
+		{"<< 6118003 OR << 71388002", []int64{6118003, 24700007, 71388002, 80146002}},
+		{"< 404684003 MINUS << 6118003", []int64{64572001, 125605004}},
+		{"< 404684003 : 363698007 |Finding site| = << 21483005", []int64{6118003, 24700007}},
+		{"< 404684003 : 363698007 = < 123037004", []int64{6118003, 24700007, 125605004}},
+		{"< 404684003 : [0..0] 363698007 = *", []int64{64572001}},
+		{"< 404684003 : { 363698007 = 272673000 }", []int64{125605004}},
+		{"< 404684003 : 363698007 != 21483005", []int64{125605004}},
+		{"< 123037004 : R 363698007 = 24700007", []int64{21483005}},
+		{"<< 6118003 . 363698007", []int64{21483005}},
+		{"<< 71388002 : 363698007 = *", []int64{}},
+	}
+	ev := ecl.New(svc.Store)
+	for _, test := range tests {
+		result, err := ev.Evaluate(context.Background(), test.ecl)
+		if err != nil {
+			t.Errorf("failed to evaluate '%s': %s", test.ecl, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("'%s': expected %v, got %v", test.ecl, test.expected, result)
+		}
+	}
+}
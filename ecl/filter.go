@@ -0,0 +1,120 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package ecl
+
+import (
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// filters are the terms of the search index to which a simple constraint reduces: concepts with
+// any of the recursive parents, or which are those parents if included, any of the direct parents
+// and all of the reference sets specified.
+type filters struct {
+	recursiveParents        []int64
+	includeRecursiveParents bool
+	directParents           []int64
+	referenceSets           []int64
+}
+
+// Filters applies the constraint to a search request as filters on the parents and reference
+// set membership of each concept, returning whether the constraint reduces to such filters.
+// A constraint reduces if it selects the descendants (optionally including the concepts themselves)
+// or children of concepts, or members of reference sets, combined by conjunction or, for parents
+// alone, disjunction, such as "<< 71388002 AND ^ 991411000000109". A constraint needing filters already specified by the
+// request does not reduce, and the request is then unchanged.
+func (c *Constraint) Filters(request *snomed.SearchRequest) bool {
+	var f filters
+	if !f.add(c.root) {
+		return false
+	}
+	if (len(f.recursiveParents) > 0 && len(request.RecursiveParentIds) > 0) || (len(f.directParents) > 0 && len(request.DirectParentIds) > 0) {
+		return false
+	}
+	if len(f.recursiveParents) > 0 {
+		request.RecursiveParentIds = f.recursiveParents
+		request.IncludeRecursiveParents = f.includeRecursiveParents
+	}
+	if len(f.directParents) > 0 {
+		request.DirectParentIds = f.directParents
+	}
+	if len(f.referenceSets) > 0 {
+		request.ReferenceSetIds = append(append([]int64{}, request.ReferenceSetIds...), f.referenceSets...)
+	}
+	return true
+}
+
+// add adds the filters for the expression, returning whether it reduces to filters
+func (f *filters) add(expr expression) bool {
+	switch x := expr.(type) {
+	case *subExpression:
+		return f.addSubExpression(x)
+	case *compound:
+		switch x.op {
+		case "AND":
+			for _, operand := range x.operands {
+				if !f.add(operand) {
+					return false
+				}
+			}
+			return true
+		case "OR":
+			return f.addParents(x.operands)
+		}
+	}
+	return false
+}
+
+// addSubExpression adds the filter for a single concept or reference set
+func (f *filters) addSubExpression(x *subExpression) bool {
+	switch {
+	case x.nested != nil:
+		return x.operator == self && !x.memberOf && f.add(x.nested)
+	case x.wildcard:
+		return x.operator == self && !x.memberOf
+	case x.memberOf:
+		if x.operator != self {
+			return false
+		}
+		f.referenceSets = append(f.referenceSets, x.conceptID)
+		return true
+	}
+	return f.addParents([]expression{x})
+}
+
+// addParents adds the filter for the descendants, the descendants or self, or the children, of any
+// of the concepts; only a single such filter of each kind can be applied.
+func (f *filters) addParents(operands []expression) bool {
+	var op operator
+	var parents []int64
+	for _, operand := range operands {
+		x, ok := operand.(*subExpression)
+		if !ok || x.nested != nil || x.wildcard || x.memberOf || (op != self && x.operator != op) {
+			return false
+		}
+		op = x.operator
+		parents = append(parents, x.conceptID)
+	}
+	switch {
+	case (op == descendantOf || op == descendantOrSelfOf) && f.recursiveParents == nil:
+		f.recursiveParents = parents
+		f.includeRecursiveParents = op == descendantOrSelfOf
+	case op == childOf && f.directParents == nil:
+		f.directParents = parents
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,510 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+// Package ecl provides a parser and evaluator for the SNOMED-CT Expression Constraint Language (ECL),
+// which defines sets of concepts, such as "<< 73211009 |Diabetes mellitus| MINUS << 46635009 |Type 1 diabetes mellitus|".
+//
+// The brief syntax of ECL is supported, as described at https://confluence.ihtsdotools.org/display/DOCECL,
+// including constraint operators, reference set membership, conjunction, disjunction and exclusion,
+// dotted attributes and refinements with attribute groups, reverse attributes and cardinality.
+// Filters on descriptions and concrete values are not supported.
+package ecl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Constraint is a parsed expression constraint
+type Constraint struct {
+	source string
+	root   expression
+}
+
+// String returns the expression constraint as originally written
+func (c *Constraint) String() string {
+	return c.source
+}
+
+// operator is a constraint operator, selecting concepts by their place in the hierarchy
+type operator int
+
+const (
+	self operator = iota
+	descendantOf
+	descendantOrSelfOf
+	childOf
+	childOrSelfOf
+	ancestorOf
+	ancestorOrSelfOf
+	parentOf
+	parentOrSelfOf
+)
+
+// operators maps the symbol for each constraint operator to the operator, longest first for matching
+var operators = []struct {
+	symbol string
+	op     operator
+}{
+	{"<<!", childOrSelfOf},
+	{">>!", parentOrSelfOf},
+	{"<<", descendantOrSelfOf},
+	{">>", ancestorOrSelfOf},
+	{"<!", childOf},
+	{">!", parentOf},
+	{"<", descendantOf},
+	{">", ancestorOf},
+}
+
+// expression is a node of a parsed expression constraint: a *subExpression, *compound, *refined or *dotted
+type expression interface{}
+
+// subExpression selects concepts by a constraint operator applied to a focus concept, to the
+// members of the reference sets specified by the focus, or to a nested expression constraint
+type subExpression struct {
+	operator  operator
+	memberOf  bool
+	conceptID int64      // the focus concept, unless wildcard or nested
+	wildcard  bool       // any concept
+	nested    expression // a nested expression constraint, in brackets
+}
+
+// compound combines expressions by conjunction ("AND"), disjunction ("OR") or exclusion ("MINUS")
+type compound struct {
+	op       string
+	operands []expression
+}
+
+// refined restricts the concepts selected by the focus to those satisfying the refinement
+type refined struct {
+	focus      expression
+	refinement refinement
+}
+
+// dotted selects the values of the attributes specified for the concepts selected by the source
+type dotted struct {
+	source     expression
+	attributes []expression
+}
+
+// refinement is a node of a parsed refinement: a *refinementSet, *attribute or *attributeGroup
+type refinement interface{}
+
+// refinementSet combines refinements by conjunction ("AND") or disjunction ("OR")
+type refinementSet struct {
+	op    string
+	items []refinement
+}
+
+// attribute requires relationships of a type selected by name with a destination selected by value,
+// or with a source selected by value if reversed, with the number of matching relationships
+// within the cardinality specified.
+type attribute struct {
+	cardinality cardinality
+	reverse     bool
+	name        expression
+	equal       bool // whether the value must be, rather than must not be, in those selected
+	value       expression
+}
+
+// attributeGroup requires relationship groups that satisfy the refinement, with the number of
+// matching groups within the cardinality specified
+type attributeGroup struct {
+	cardinality cardinality
+	refinement  refinement
+}
+
+// cardinality is the minimum and maximum number of matches, with a maximum of -1 for no maximum.
+// The default is at least one.
+type cardinality struct {
+	min int
+	max int
+}
+
+var defaultCardinality = cardinality{min: 1, max: -1}
+
+// includes determines whether the number specified is within the cardinality
+func (c cardinality) includes(n int) bool {
+	return n >= c.min && (c.max < 0 || n <= c.max)
+}
+
+// Parse parses an expression constraint
+func Parse(s string) (*Constraint, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %s", p.peek())
+	}
+	return &Constraint{source: s, root: root}, nil
+}
+
+// token kinds
+const (
+	symbolToken = iota
+	numberToken
+	keywordToken
+)
+
+type token struct {
+	kind     int
+	text     string
+	position int // byte offset of the token within the expression constraint
+}
+
+func (t token) String() string {
+	return fmt.Sprintf("'%s' at position %d", t.text, t.position+1)
+}
+
+// symbols are the punctuation of ECL, longest first for matching
+var symbols = []string{"<<!", ">>!", "<<", ">>", "<!", ">!", "!=", "..", "<", ">", "^", "*", "(", ")", "{", "}", "[", "]", ":", ",", "=", "."}
+
+// lex splits an expression constraint into tokens, ignoring whitespace, comments and terms
+func lex(s string) ([]token, error) {
+	var result []token
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case strings.HasPrefix(s[i:], "/*"):
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment at position %d", i+1)
+			}
+			i += end + 4
+		case r == '|':
+			end := strings.IndexByte(s[i+1:], '|')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated term at position %d", i+1)
+			}
+			i += end + 2
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			result = append(result, token{kind: numberToken, text: s[i:j], position: i})
+			i = j
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(s) && unicode.IsLetter(rune(s[j])) {
+				j++
+			}
+			result = append(result, token{kind: keywordToken, text: strings.ToUpper(s[i:j]), position: i})
+			i = j
+		default:
+			matched := false
+			for _, symbol := range symbols {
+				if strings.HasPrefix(s[i:], symbol) {
+					result = append(result, token{kind: symbolToken, text: symbol, position: i})
+					i += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected '%c' at position %d", r, i+1)
+			}
+		}
+	}
+	return result, nil
+}
+
+// parser is a recursive descent parser for the tokens of an expression constraint
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{text: "end of expression"}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the symbol or keyword specified
+func (p *parser) accept(text string) bool {
+	if !p.done() && p.tokens[p.pos].kind != numberToken && p.tokens[p.pos].text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(text) {
+		return p.errorf("expected '%s', got %s", text, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid expression constraint: "+format, args...)
+}
+
+// conjunctionOrDisjunction consumes and returns the next token if it combines expressions
+// or refinements by conjunction or disjunction, with "," equivalent to "AND"
+func (p *parser) conjunctionOrDisjunction() (string, bool) {
+	switch {
+	case p.accept("AND"), p.accept(","):
+		return "AND", true
+	case p.accept("OR"):
+		return "OR", true
+	}
+	return "", false
+}
+
+// parseExpression parses a refined, compound, dotted or simple expression constraint
+func (p *parser) parseExpression() (expression, error) {
+	first, err := p.parseDotted()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept(":") {
+		r, err := p.parseRefinement(false)
+		if err != nil {
+			return nil, err
+		}
+		return &refined{focus: first, refinement: r}, nil
+	}
+	if p.accept("MINUS") {
+		second, err := p.parseDotted()
+		if err != nil {
+			return nil, err
+		}
+		return &compound{op: "MINUS", operands: []expression{first, second}}, nil
+	}
+	op, ok := p.conjunctionOrDisjunction()
+	if !ok {
+		return first, nil
+	}
+	result := &compound{op: op, operands: []expression{first}}
+	for {
+		next, err := p.parseDotted()
+		if err != nil {
+			return nil, err
+		}
+		result.operands = append(result.operands, next)
+		nextOp, ok := p.conjunctionOrDisjunction()
+		if !ok {
+			return result, nil
+		}
+		if nextOp != op {
+			return nil, p.errorf("AND and OR cannot be combined without brackets")
+		}
+	}
+}
+
+// parseDotted parses a sub-expression, optionally followed by one or more dotted attributes
+func (p *parser) parseDotted() (expression, error) {
+	source, err := p.parseSubExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.accept(".") {
+		return source, nil
+	}
+	result := &dotted{source: source}
+	for {
+		attr, err := p.parseSubExpression()
+		if err != nil {
+			return nil, err
+		}
+		result.attributes = append(result.attributes, attr)
+		if !p.accept(".") {
+			return result, nil
+		}
+	}
+}
+
+// parseOperator parses an optional constraint operator
+func (p *parser) parseOperator() operator {
+	for _, o := range operators {
+		if p.accept(o.symbol) {
+			return o.op
+		}
+	}
+	return self
+}
+
+// parseSubExpression parses an optional constraint operator and member of function, applied to
+// a concept reference, a wildcard or a nested expression constraint in brackets
+func (p *parser) parseSubExpression() (expression, error) {
+	result := &subExpression{operator: p.parseOperator(), memberOf: p.accept("^")}
+	switch {
+	case p.accept("*"):
+		result.wildcard = true
+	case p.accept("("):
+		nested, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		result.nested = nested
+	default:
+		id, err := p.parseConceptID()
+		if err != nil {
+			return nil, err
+		}
+		result.conceptID = id
+	}
+	return result, nil
+}
+
+// parseConceptID parses a concept identifier; any term following it has already been discarded
+func (p *parser) parseConceptID() (int64, error) {
+	t := p.peek()
+	if p.done() || t.kind != numberToken {
+		return 0, p.errorf("expected concept identifier, got %s", t)
+	}
+	p.pos++
+	id, err := strconv.ParseInt(t.text, 10, 64)
+	if err != nil {
+		return 0, p.errorf("invalid concept identifier %s", t)
+	}
+	return id, nil
+}
+
+// parseRefinement parses refinements combined by conjunction or disjunction; attribute groups
+// are not permitted within an attribute group.
+func (p *parser) parseRefinement(inGroup bool) (refinement, error) {
+	first, err := p.parseSubRefinement(inGroup)
+	if err != nil {
+		return nil, err
+	}
+	op, ok := p.conjunctionOrDisjunction()
+	if !ok {
+		return first, nil
+	}
+	result := &refinementSet{op: op, items: []refinement{first}}
+	for {
+		next, err := p.parseSubRefinement(inGroup)
+		if err != nil {
+			return nil, err
+		}
+		result.items = append(result.items, next)
+		nextOp, ok := p.conjunctionOrDisjunction()
+		if !ok {
+			return result, nil
+		}
+		if nextOp != op {
+			return nil, p.errorf("AND and OR cannot be combined without brackets")
+		}
+	}
+}
+
+// parseSubRefinement parses a refinement in brackets, an attribute group or an attribute
+func (p *parser) parseSubRefinement(inGroup bool) (refinement, error) {
+	if p.accept("(") {
+		r, err := p.parseRefinement(inGroup)
+		if err != nil {
+			return nil, err
+		}
+		return r, p.expect(")")
+	}
+	c, err := p.parseCardinality()
+	if err != nil {
+		return nil, err
+	}
+	if p.accept("{") {
+		if inGroup {
+			return nil, p.errorf("attribute groups cannot be nested")
+		}
+		r, err := p.parseRefinement(true)
+		if err != nil {
+			return nil, err
+		}
+		return &attributeGroup{cardinality: c, refinement: r}, p.expect("}")
+	}
+	result := &attribute{cardinality: c, reverse: p.accept("R")}
+	if result.name, err = p.parseAttributeName(); err != nil {
+		return nil, err
+	}
+	switch {
+	case p.accept("="):
+		result.equal = true
+	case p.accept("!="):
+	default:
+		return nil, p.errorf("expected '=' or '!=', got %s", p.peek())
+	}
+	if result.value, err = p.parseSubExpression(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// parseAttributeName parses an optional constraint operator applied to an attribute or a wildcard
+func (p *parser) parseAttributeName() (expression, error) {
+	result := &subExpression{operator: p.parseOperator()}
+	if p.accept("*") {
+		result.wildcard = true
+		return result, nil
+	}
+	id, err := p.parseConceptID()
+	if err != nil {
+		return nil, err
+	}
+	result.conceptID = id
+	return result, nil
+}
+
+// parseCardinality parses an optional cardinality such as [1..3] or [0..*]
+func (p *parser) parseCardinality() (cardinality, error) {
+	if !p.accept("[") {
+		return defaultCardinality, nil
+	}
+	var result cardinality
+	var err error
+	if result.min, err = p.parseInt(); err != nil {
+		return result, err
+	}
+	if err := p.expect(".."); err != nil {
+		return result, err
+	}
+	if p.accept("*") {
+		result.max = -1
+	} else if result.max, err = p.parseInt(); err != nil {
+		return result, err
+	}
+	if result.max >= 0 && result.max < result.min {
+		return result, p.errorf("cardinality maximum less than minimum")
+	}
+	return result, p.expect("]")
+}
+
+func (p *parser) parseInt() (int, error) {
+	t := p.peek()
+	if p.done() || t.kind != numberToken {
+		return 0, p.errorf("expected number, got %s", t)
+	}
+	p.pos++
+	return strconv.Atoi(t.text)
+}
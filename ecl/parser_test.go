@@ -0,0 +1,97 @@
+package ecl
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		"24700007",
+		"<< 24700007 |Multiple sclerosis|",
+		"<<! 404684003",
+		"<!404684003",
+		">> 24700007",
+		">! 24700007",
+		"^ 991411000000109 |Example refset|",
+		"*",
+		"< 404684003 AND ^ 991411000000109",
+		"< 404684003 and ^ 991411000000109 and << 6118003",
+		"<< 404684003 OR << 71388002",
+		"<< 404684003 MINUS << 6118003",
+		"(<< 404684003 MINUS << 6118003) OR 24700007",
+		"< 404684003 : 363698007 = << 21483005",
+		"< 404684003 : 363698007 = << 21483005, 116676008 != *",
+		"< 404684003 : { 363698007 = << 21483005 } OR [0..0] 363698007 = *",
+		"< 404684003 : [1..3] { 363698007 = 21483005, 116676008 = 72704001 }",
+		"< 123037004 : R 363698007 = < 404684003",
+		"< 404684003 . 363698007",
+		"(< 404684003 : 363698007 = *) . 363698007",
+		"/* a comment */ << 24700007",
+	}
+	for _, s := range valid {
+		c, err := Parse(s)
+		if err != nil {
+			t.Errorf("failed to parse '%s': %s", s, err)
+			continue
+		}
+		if c.String() != s {
+			t.Errorf("expected '%s', got '%s'", s, c.String())
+		}
+	}
+	invalid := []string{
+		"",
+		"<<",
+		"<< 24700007 |Multiple sclerosis",
+		"<< 404684003 AND << 71388002 OR 24700007",
+		"< 404684003 : 363698007",
+		"< 404684003 : { 363698007 = * , { 116676008 = * } }",
+		"< 404684003 : [2..1] 363698007 = *",
+		"(<< 404684003",
+		"<< 404684003 )",
+		"<< abc",
+	}
+	for _, s := range invalid {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected error parsing '%s'", s)
+		}
+	}
+}
+
+func TestFilters(t *testing.T) {
+	tests := []struct {
+		ecl      string
+		request  *snomed.SearchRequest
+		expected *snomed.SearchRequest // nil if the constraint does not reduce to filters
+	}{
+		{"< 71388002", &snomed.SearchRequest{}, &snomed.SearchRequest{RecursiveParentIds: []int64{71388002}}},
+		{"< 71388002 OR < 404684003", &snomed.SearchRequest{}, &snomed.SearchRequest{RecursiveParentIds: []int64{71388002, 404684003}}},
+		{"<! 404684003 AND ^ 991411000000109", &snomed.SearchRequest{ReferenceSetIds: []int64{1}}, &snomed.SearchRequest{DirectParentIds: []int64{404684003}, ReferenceSetIds: []int64{1, 991411000000109}}},
+		{"(< 71388002 OR < 404684003) AND ^ 991411000000109 AND *", &snomed.SearchRequest{}, &snomed.SearchRequest{RecursiveParentIds: []int64{71388002, 404684003}, ReferenceSetIds: []int64{991411000000109}}},
+		{"< 71388002", &snomed.SearchRequest{RecursiveParentIds: []int64{404684003}}, nil},
+		{"< 71388002 AND < 404684003", &snomed.SearchRequest{}, nil},
+		{"< 71388002 OR <! 404684003", &snomed.SearchRequest{}, nil},
+		{"<< 71388002", &snomed.SearchRequest{}, &snomed.SearchRequest{RecursiveParentIds: []int64{71388002}, IncludeRecursiveParents: true}},
+		{"<< 71388002 OR << 404684003", &snomed.SearchRequest{}, &snomed.SearchRequest{RecursiveParentIds: []int64{71388002, 404684003}, IncludeRecursiveParents: true}},
+		{"<< 71388002 OR < 404684003", &snomed.SearchRequest{}, nil},
+		{"< 71388002 MINUS < 80146002", &snomed.SearchRequest{}, nil},
+		{"< 404684003 : 363698007 = *", &snomed.SearchRequest{}, nil},
+	}
+	for _, test := range tests {
+		c, err := Parse(test.ecl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := *test.request
+		reduced := c.Filters(test.request)
+		if test.expected == nil {
+			if reduced || !reflect.DeepEqual(*test.request, original) {
+				t.Errorf("'%s' unexpectedly reduced to filters: %v", test.ecl, test.request)
+			}
+		} else if !reduced || !reflect.DeepEqual(test.request, test.expected) {
+			t.Errorf("'%s': expected filters %v, got %v", test.ecl, test.expected, test.request)
+		}
+	}
+}
@@ -0,0 +1,488 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package expression
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/storage"
+)
+
+// NormalForm returns the normal form of the expression, in which each focus concept is replaced by its
+// proximal primitive supertypes and refined by its active defining relationships, in their groups,
+// as well as by the refinements of the expression, normalised in turn. Redundant focus concepts,
+// attributes and groups, those subsuming others, are removed, and the remainder are written in order,
+// so that equivalent expressions have the same normal form.
+// See https://confluence.ihtsdotools.org/display/DOCTSG/Normal+Forms
+func NormalForm(store storage.Store, e *snomed.Expression) (*snomed.Expression, error) {
+	return newNormaliser(store).normalForm(e)
+}
+
+// Subsumes determines whether expression a subsumes expression b; that is, whether b is a subtype
+// of, or is equivalent to, a. A pre-coordinated concept is an expression with only a focus concept.
+func Subsumes(store storage.Store, a *snomed.Expression, b *snomed.Expression) (bool, error) {
+	n := newNormaliser(store)
+	na, err := n.normalForm(a)
+	if err != nil {
+		return false, err
+	}
+	nb, err := n.normalForm(b)
+	if err != nil {
+		return false, err
+	}
+	return n.subsumes(na, nb)
+}
+
+// normaliser computes normal forms and subsumption, caching the ancestors of each concept
+type normaliser struct {
+	store     storage.Store
+	ancestors map[int64]map[int64]bool
+}
+
+func newNormaliser(store storage.Store) *normaliser {
+	return &normaliser{store: store, ancestors: make(map[int64]map[int64]bool)}
+}
+
+// normalised is an expression in normal form: its focus concepts, and its ungrouped and grouped refinements
+type normalised struct {
+	status      snomed.Expression_DefinitionStatus
+	focus       []int64
+	refinements []*snomed.Expression_Refinement
+	groups      [][]*snomed.Expression_Refinement
+}
+
+func (n *normaliser) normalForm(e *snomed.Expression) (*snomed.Expression, error) {
+	nf, err := n.normalise(e)
+	if err != nil {
+		return nil, err
+	}
+	return nf.expression(), nil
+}
+
+func (n *normaliser) normalise(e *snomed.Expression) (*normalised, error) {
+	nf := &normalised{status: e.DefinitionStatus}
+	for _, clause := range e.Terms {
+		concept, err := n.store.GetConcept(clause.GetConcept().GetId())
+		if err != nil {
+			return nil, err
+		}
+		primitives, err := n.proximalPrimitives(concept)
+		if err != nil {
+			return nil, err
+		}
+		nf.focus = append(nf.focus, primitives...)
+		if err := n.addDefinition(nf, concept.Id); err != nil {
+			return nil, err
+		}
+		refinements, err := n.normaliseRefinements(clause.Refinements)
+		if err != nil {
+			return nil, err
+		}
+		nf.refinements = append(nf.refinements, refinements...)
+		for _, group := range clause.RefinedGroups {
+			refinements, err := n.normaliseRefinements(group.Refinement)
+			if err != nil {
+				return nil, err
+			}
+			nf.groups = append(nf.groups, refinements)
+		}
+	}
+	return nf, n.removeRedundant(nf)
+}
+
+// proximalPrimitives returns the concept itself if it is primitive, or otherwise its closest
+// primitive supertypes
+func (n *normaliser) proximalPrimitives(concept *snomed.Concept) ([]int64, error) {
+	if !concept.IsSufficientlyDefined() {
+		return []int64{concept.Id}, nil
+	}
+	rels, err := n.store.GetParentRelationships(concept)
+	if err != nil {
+		return nil, err
+	}
+	var result []int64
+	for _, rel := range rels {
+		if !rel.Active || rel.TypeId != snomed.IsA || !rel.IsDefiningRelationship() {
+			continue
+		}
+		parent, err := n.store.GetConcept(rel.DestinationId)
+		if err != nil {
+			return nil, err
+		}
+		primitives, err := n.proximalPrimitives(parent)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, primitives...)
+	}
+	return result, nil
+}
+
+// addDefinition adds the active defining relationships of the concept, other than IS-A, as refinements,
+// with those in relationship group zero ungrouped
+func (n *normaliser) addDefinition(nf *normalised, conceptID int64) error {
+	rels, err := n.store.GetParentRelationships(&snomed.Concept{Id: conceptID})
+	if err != nil {
+		return err
+	}
+	groups := make(map[int64]int)
+	for _, rel := range rels {
+		if !rel.Active || rel.TypeId == snomed.IsA || !rel.IsDefiningRelationship() {
+			continue
+		}
+		r := &snomed.Expression_Refinement{
+			Attribute: &snomed.Concept{Id: rel.TypeId},
+			Value:     &snomed.Expression_Refinement_ConceptValue{ConceptValue: &snomed.Concept{Id: rel.DestinationId}},
+		}
+		if rel.RelationshipGroup == 0 {
+			nf.refinements = append(nf.refinements, r)
+			continue
+		}
+		i, ok := groups[rel.RelationshipGroup]
+		if !ok {
+			i = len(nf.groups)
+			groups[rel.RelationshipGroup] = i
+			nf.groups = append(nf.groups, nil)
+		}
+		nf.groups[i] = append(nf.groups[i], r)
+	}
+	return nil
+}
+
+// normaliseRefinements returns the refinements with any nested expressions in normal form
+func (n *normaliser) normaliseRefinements(refinements []*snomed.Expression_Refinement) ([]*snomed.Expression_Refinement, error) {
+	result := make([]*snomed.Expression_Refinement, 0, len(refinements))
+	for _, r := range refinements {
+		value := r.Value
+		if v, ok := r.Value.(*snomed.Expression_Refinement_ExpressionValue); ok {
+			nf, err := n.normalForm(v.ExpressionValue)
+			if err != nil {
+				return nil, err
+			}
+			value = &snomed.Expression_Refinement_ExpressionValue{ExpressionValue: nf}
+		}
+		result = append(result, &snomed.Expression_Refinement{Attribute: &snomed.Concept{Id: r.GetAttribute().GetId()}, Value: value})
+	}
+	return result, nil
+}
+
+// removeRedundant removes focus concepts, refinements and groups that subsume others
+func (n *normaliser) removeRedundant(nf *normalised) error {
+	remove, err := redundant(len(nf.focus), func(i, j int) (bool, error) { return n.isA(nf.focus[j], nf.focus[i]) })
+	if err != nil {
+		return err
+	}
+	focus := nf.focus[:0]
+	for i, id := range nf.focus {
+		if !remove[i] {
+			focus = append(focus, id)
+		}
+	}
+	nf.focus = focus
+	if nf.refinements, err = n.removeRedundantRefinements(nf.refinements); err != nil {
+		return err
+	}
+	for i, group := range nf.groups {
+		if nf.groups[i], err = n.removeRedundantRefinements(group); err != nil {
+			return err
+		}
+	}
+	remove, err = redundant(len(nf.groups), func(i, j int) (bool, error) { return n.groupSubsumes(nf.groups[i], nf.groups[j]) })
+	if err != nil {
+		return err
+	}
+	groups := nf.groups[:0]
+	for i, group := range nf.groups {
+		if !remove[i] {
+			groups = append(groups, group)
+		}
+	}
+	nf.groups = groups
+	return nil
+}
+
+func (n *normaliser) removeRedundantRefinements(refinements []*snomed.Expression_Refinement) ([]*snomed.Expression_Refinement, error) {
+	remove, err := redundant(len(refinements), func(i, j int) (bool, error) { return n.refinementSubsumes(refinements[i], refinements[j]) })
+	if err != nil {
+		return nil, err
+	}
+	result := refinements[:0]
+	for i, r := range refinements {
+		if !remove[i] {
+			result = append(result, r)
+		}
+	}
+	return result, nil
+}
+
+// redundant returns which of a number of items subsume another, and so are redundant.
+// Of items that subsume each other, being equivalent, only the first is kept.
+func redundant(count int, subsumes func(i int, j int) (bool, error)) ([]bool, error) {
+	result := make([]bool, count)
+	for i := 0; i < count; i++ {
+		for j := 0; j < count && !result[i]; j++ {
+			if i == j || result[j] {
+				continue
+			}
+			ok, err := subsumes(i, j)
+			if err != nil {
+				return nil, err
+			}
+			if ok && i < j {
+				if ok, err = subsumes(j, i); err != nil {
+					return nil, err
+				}
+				ok = !ok
+			}
+			result[i] = ok
+		}
+	}
+	return result, nil
+}
+
+// expression returns the expression in normal form, with its focus concepts, refinements and groups in order
+func (nf *normalised) expression() *snomed.Expression {
+	sort.Slice(nf.focus, func(i, j int) bool { return nf.focus[i] < nf.focus[j] })
+	sortRefinements(nf.refinements)
+	for _, group := range nf.groups {
+		sortRefinements(group)
+	}
+	sort.Slice(nf.groups, func(i, j int) bool { return groupString(nf.groups[i]) < groupString(nf.groups[j]) })
+	e := &snomed.Expression{DefinitionStatus: nf.status}
+	for _, id := range nf.focus {
+		e.Terms = append(e.Terms, &snomed.Expression_Clause{Concept: &snomed.Concept{Id: id}})
+	}
+	if len(e.Terms) > 0 {
+		e.Terms[0].Refinements = nf.refinements
+		for _, group := range nf.groups {
+			e.Terms[0].RefinedGroups = append(e.Terms[0].RefinedGroups, &snomed.Expression_RefinementGroup{Refinement: group})
+		}
+	}
+	return e
+}
+
+func sortRefinements(refinements []*snomed.Expression_Refinement) {
+	sort.Slice(refinements, func(i, j int) bool {
+		return groupString(refinements[i:i+1]) < groupString(refinements[j:j+1])
+	})
+}
+
+// groupString returns the refinements written in the compositional grammar, for ordering
+func groupString(refinements []*snomed.Expression_Refinement) string {
+	var b strings.Builder
+	writeAttributes(&b, refinements)
+	return b.String()
+}
+
+// subsumes determines whether expression a, in normal form, subsumes expression b, in normal form.
+// Each focus concept of a must subsume a focus concept of b, each ungrouped refinement of a must
+// subsume a refinement of b, grouped or not, and each group of a must subsume a group of b.
+// An expression that is only a subtype of its definition ("<<<") is not fully defined, and so
+// subsumes only itself and its structural subtypes.
+func (n *normaliser) subsumes(a *snomed.Expression, b *snomed.Expression) (bool, error) {
+	na, nb := normalisedFrom(a), normalisedFrom(b)
+	if na.status == snomed.Expression_SUBTYPE_OF {
+		return structurallySubsumes(na, nb), nil
+	}
+	for _, fa := range na.focus {
+		ok, err := anyOf(len(nb.focus), func(i int) (bool, error) { return n.isA(nb.focus[i], fa) })
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	all := append([]*snomed.Expression_Refinement{}, nb.refinements...)
+	for _, group := range nb.groups {
+		all = append(all, group...)
+	}
+	for _, ra := range na.refinements {
+		ok, err := anyOf(len(all), func(i int) (bool, error) { return n.refinementSubsumes(ra, all[i]) })
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	for _, ga := range na.groups {
+		ok, err := anyOf(len(nb.groups), func(i int) (bool, error) { return n.groupSubsumes(ga, nb.groups[i]) })
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// structurallySubsumes determines whether b, in normal form, is a structural subtype of the subtype
+// expression a, in normal form: b must also be a subtype expression, with all of the focus concepts,
+// ungrouped refinements and groups of a, and optionally more.
+func structurallySubsumes(a *normalised, b *normalised) bool {
+	if b.status != snomed.Expression_SUBTYPE_OF {
+		return false
+	}
+	focus := make(map[int64]bool, len(b.focus))
+	for _, id := range b.focus {
+		focus[id] = true
+	}
+	for _, id := range a.focus {
+		if !focus[id] {
+			return false
+		}
+	}
+	refinements := make(map[string]bool, len(b.refinements))
+	for i := range b.refinements {
+		refinements[groupString(b.refinements[i:i+1])] = true
+	}
+	for i := range a.refinements {
+		if !refinements[groupString(a.refinements[i:i+1])] {
+			return false
+		}
+	}
+	groups := make(map[string]bool, len(b.groups))
+	for _, group := range b.groups {
+		groups[groupString(group)] = true
+	}
+	for _, group := range a.groups {
+		if !groups[groupString(group)] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalisedFrom returns the parts of an expression already in normal form
+func normalisedFrom(e *snomed.Expression) *normalised {
+	nf := &normalised{status: e.DefinitionStatus}
+	for _, clause := range e.Terms {
+		nf.focus = append(nf.focus, clause.GetConcept().GetId())
+		nf.refinements = append(nf.refinements, clause.Refinements...)
+		for _, group := range clause.RefinedGroups {
+			nf.groups = append(nf.groups, group.Refinement)
+		}
+	}
+	return nf
+}
+
+// groupSubsumes determines whether each refinement of group a subsumes a refinement of group b
+func (n *normaliser) groupSubsumes(a []*snomed.Expression_Refinement, b []*snomed.Expression_Refinement) (bool, error) {
+	for _, ra := range a {
+		ok, err := anyOf(len(b), func(i int) (bool, error) { return n.refinementSubsumes(ra, b[i]) })
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// refinementSubsumes determines whether refinement a subsumes refinement b, which requires the
+// attribute and value of b to be subtypes of, or equal to, those of a. Concrete values must be equal.
+func (n *normaliser) refinementSubsumes(a *snomed.Expression_Refinement, b *snomed.Expression_Refinement) (bool, error) {
+	ok, err := n.isA(b.GetAttribute().GetId(), a.GetAttribute().GetId())
+	if err != nil || !ok {
+		return false, err
+	}
+	switch va := a.Value.(type) {
+	case *snomed.Expression_Refinement_ConceptValue:
+		switch vb := b.Value.(type) {
+		case *snomed.Expression_Refinement_ConceptValue:
+			return n.isA(vb.ConceptValue.GetId(), va.ConceptValue.GetId())
+		case *snomed.Expression_Refinement_ExpressionValue:
+			return n.expressionSubsumes(conceptExpression(va.ConceptValue), vb.ExpressionValue)
+		}
+		return false, nil
+	case *snomed.Expression_Refinement_ExpressionValue:
+		switch vb := b.Value.(type) {
+		case *snomed.Expression_Refinement_ConceptValue:
+			return n.expressionSubsumes(va.ExpressionValue, conceptExpression(vb.ConceptValue))
+		case *snomed.Expression_Refinement_ExpressionValue:
+			return n.expressionSubsumes(va.ExpressionValue, vb.ExpressionValue)
+		}
+		return false, nil
+	case *snomed.Expression_Refinement_StringValue:
+		vb, ok := b.Value.(*snomed.Expression_Refinement_StringValue)
+		return ok && vb.StringValue == va.StringValue, nil
+	case *snomed.Expression_Refinement_IntValue:
+		vb, ok := b.Value.(*snomed.Expression_Refinement_IntValue)
+		return ok && vb.IntValue == va.IntValue, nil
+	case *snomed.Expression_Refinement_DoubleValue:
+		vb, ok := b.Value.(*snomed.Expression_Refinement_DoubleValue)
+		return ok && vb.DoubleValue == va.DoubleValue, nil
+	}
+	return false, nil
+}
+
+// expressionSubsumes determines whether the expression a subsumes expression b, normalising both
+func (n *normaliser) expressionSubsumes(a *snomed.Expression, b *snomed.Expression) (bool, error) {
+	na, err := n.normalForm(a)
+	if err != nil {
+		return false, err
+	}
+	nb, err := n.normalForm(b)
+	if err != nil {
+		return false, err
+	}
+	return n.subsumes(na, nb)
+}
+
+// conceptExpression returns an expression for a pre-coordinated concept
+func conceptExpression(c *snomed.Concept) *snomed.Expression {
+	return &snomed.Expression{Terms: []*snomed.Expression_Clause{{Concept: c}}}
+}
+
+// isA determines whether concept a is concept b, or one of its descendants, using active IS-A relationships
+func (n *normaliser) isA(a int64, b int64) (bool, error) {
+	if a == b {
+		return true, nil
+	}
+	ancestors, err := n.ancestorsOf(a)
+	if err != nil {
+		return false, err
+	}
+	return ancestors[b], nil
+}
+
+func (n *normaliser) ancestorsOf(conceptID int64) (map[int64]bool, error) {
+	if ancestors, ok := n.ancestors[conceptID]; ok {
+		return ancestors, nil
+	}
+	ancestors := make(map[int64]bool)
+	pending := []int64{conceptID}
+	for len(pending) > 0 {
+		id := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		rels, err := n.store.GetParentRelationships(&snomed.Concept{Id: id})
+		if err != nil {
+			return nil, err
+		}
+		for _, rel := range rels {
+			if rel.Active && rel.TypeId == snomed.IsA && !ancestors[rel.DestinationId] {
+				ancestors[rel.DestinationId] = true
+				pending = append(pending, rel.DestinationId)
+			}
+		}
+	}
+	n.ancestors[conceptID] = ancestors
+	return ancestors, nil
+}
+
+// anyOf determines whether the function is true for any of the indices up to n
+func anyOf(n int, fn func(int) (bool, error)) (bool, error) {
+	for i := 0; i < n; i++ {
+		ok, err := fn(i)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
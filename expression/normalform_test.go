@@ -0,0 +1,78 @@
+package expression_test
+
+import (
+	"testing"
+
+	"github.com/wardle/go-terminology/expression"
+	"github.com/wardle/go-terminology/terminology"
+	"github.com/wardle/go-terminology/terminology/terminologytest"
+)
+
+func TestNormalForm(t *testing.T) {
+	svc := terminologytest.New(t, terminology.Options{InMemoryIndex: true})
+	defer svc.Close()
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"24700007 |Multiple sclerosis|", "24700007 : { 363698007 = 21483005 }"},
+		{"6118003 |Demyelinating disease of CNS|", "64572001 : { 363698007 = 21483005 }"},
+		{"64572001 + 404684003 : { 363698007 = 123037004 }, { 363698007 = 21483005 }", "64572001 : { 363698007 = 21483005 }"},
+		{"404684003 : 363698007 = (123037004 : 363698007 = 21483005)", "404684003 : 363698007 = (123037004 : 363698007 = 21483005)"},
+	}
+	for _, test := range tests {
+		e, err := expression.Parse(test.expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nf, err := expression.NormalForm(svc.Store, e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := expression.String(nf); s != test.expected {
+			t.Errorf("normal form of '%s': expected '%s', got '%s'", test.expression, test.expected, s)
+		}
+	}
+}
+
+func TestSubsumes(t *testing.T) {
+	svc := terminologytest.New(t, terminology.Options{InMemoryIndex: true})
+	defer svc.Close()
+	tests := []struct {
+		a, b     string
+		subsumes bool
+	}{
+		{"6118003", "64572001 : { 363698007 = 21483005 }", true},
+		{"64572001 : { 363698007 = 21483005 }", "6118003", true},
+		{"64572001", "24700007", true},
+		{"24700007", "64572001", false},
+		{"6118003", "24700007", true},
+		{"404684003 : { 363698007 = 123037004 }", "125605004", true},
+		{"64572001 : { 363698007 = 123037004 }", "125605004", false},
+		{"64572001 : { 363698007 = 123037004 }", "64572001 : { 363698007 = 272673000 }", true},
+		{"64572001 : { 363698007 = 272673000 }", "64572001 : { 363698007 = 123037004 }", false},
+		{"6118003", "24700007 : { 363698007 = 272673000 }", true},
+		{"<<< 64572001", "24700007", false},
+		{"<<< 64572001", "<<< 64572001", true},
+		{"<<< 64572001", "<<< 64572001 : { 363698007 = 21483005 }", true},
+		{"<<< 64572001 : { 363698007 = 21483005 }", "<<< 64572001", false},
+		{"64572001", "<<< 24700007", true},
+	}
+	for _, test := range tests {
+		a, err := expression.Parse(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := expression.Parse(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		subsumes, err := expression.Subsumes(svc.Store, a, b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if subsumes != test.subsumes {
+			t.Errorf("'%s' subsumes '%s': expected %t, got %t", test.a, test.b, test.subsumes, subsumes)
+		}
+	}
+}
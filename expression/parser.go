@@ -0,0 +1,368 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+// Package expression converts between snomed.Expression and the SNOMED-CT compositional grammar,
+// in which an expression such as "71388002 |Procedure| : 405813007 |Procedure site| = 15497006 |Ovarian structure|"
+// refines one or more focus concepts with attribute-value pairs, optionally within attribute groups.
+//
+// See https://confluence.ihtsdotools.org/display/DOCSCG/Compositional+Grammar+-+Specification+and+Guide
+//
+// The focus concepts of an expression are held as its terms, with all of its refinements held by the
+// first. Terms are ignored when parsing, and are not written.
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// token kinds
+const (
+	symbolToken  = iota
+	conceptToken // a concept identifier
+	numberToken  // a concrete numeric value, such as #500 or #0.5
+	stringToken  // a concrete string value, without its quotes
+)
+
+type token struct {
+	kind     int
+	text     string
+	position int // byte offset of the token within the expression
+}
+
+func (t token) String() string {
+	return fmt.Sprintf("'%s' at position %d", t.text, t.position+1)
+}
+
+// symbols are the punctuation of the compositional grammar, longest first for matching
+var symbols = []string{"===", "<<<", "+", ":", ",", "{", "}", "=", "(", ")"}
+
+// lex splits an expression into tokens, ignoring whitespace and terms
+func lex(s string) ([]token, error) {
+	var result []token
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '|':
+			end := strings.IndexByte(s[i+1:], '|')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated term at position %d", i+1)
+			}
+			i += end + 2
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+				j++
+			}
+			result = append(result, token{kind: conceptToken, text: s[i:j], position: i})
+			i = j
+		case r == '#':
+			j := i + 1
+			if j < len(s) && (s[j] == '-' || s[j] == '+') {
+				j++
+			}
+			for j < len(s) && (s[j] >= '0' && s[j] <= '9' || s[j] == '.') {
+				j++
+			}
+			result = append(result, token{kind: numberToken, text: s[i+1 : j], position: i})
+			i = j
+		case r == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				b.WriteByte(s[j])
+			}
+			if j >= len(s) {
+				return nil, fmt.Errorf("unterminated string at position %d", i+1)
+			}
+			result = append(result, token{kind: stringToken, text: b.String(), position: i})
+			i = j + 1
+		default:
+			matched := false
+			for _, symbol := range symbols {
+				if strings.HasPrefix(s[i:], symbol) {
+					result = append(result, token{kind: symbolToken, text: symbol, position: i})
+					i += len(symbol)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected '%c' at position %d", r, i+1)
+			}
+		}
+	}
+	return result, nil
+}
+
+// Parse parses an expression written in the compositional grammar. Each concept of the
+// resulting expression has only its identifier; use Resolve to fetch the concepts from a store.
+func Parse(s string) (*snomed.Expression, error) {
+	tokens, err := lex(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	var status snomed.Expression_DefinitionStatus
+	if p.accept("<<<") {
+		status = snomed.Expression_SUBTYPE_OF
+	} else {
+		p.accept("===")
+	}
+	e, err := p.parseSubExpression()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %s", p.peek())
+	}
+	e.DefinitionStatus = status
+	return e, nil
+}
+
+// parser is a recursive descent parser for the tokens of an expression
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	if p.done() {
+		return token{text: "end of expression"}
+	}
+	return p.tokens[p.pos]
+}
+
+// accept consumes the next token if it is the symbol specified
+func (p *parser) accept(symbol string) bool {
+	if p.next(symbol) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// next determines whether the next token is the symbol specified, without consuming it
+func (p *parser) next(symbol string) bool {
+	return !p.done() && p.tokens[p.pos].kind == symbolToken && p.tokens[p.pos].text == symbol
+}
+
+func (p *parser) expect(symbol string) error {
+	if !p.accept(symbol) {
+		return p.errorf("expected '%s', got %s", symbol, p.peek())
+	}
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid expression: "+format, args...)
+}
+
+// parseSubExpression parses one or more focus concepts, with an optional refinement
+func (p *parser) parseSubExpression() (*snomed.Expression, error) {
+	e := &snomed.Expression{}
+	for {
+		c, err := p.parseConcept()
+		if err != nil {
+			return nil, err
+		}
+		e.Terms = append(e.Terms, &snomed.Expression_Clause{Concept: c})
+		if !p.accept("+") {
+			break
+		}
+	}
+	if p.accept(":") {
+		if err := p.parseRefinement(e.Terms[0]); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// parseRefinement parses ungrouped attributes followed by attribute groups, separated by
+// commas, which are optional between groups
+func (p *parser) parseRefinement(clause *snomed.Expression_Clause) error {
+	for {
+		if p.accept("{") {
+			group := &snomed.Expression_RefinementGroup{}
+			for {
+				r, err := p.parseAttribute()
+				if err != nil {
+					return err
+				}
+				group.Refinement = append(group.Refinement, r)
+				if !p.accept(",") {
+					break
+				}
+			}
+			if err := p.expect("}"); err != nil {
+				return err
+			}
+			clause.RefinedGroups = append(clause.RefinedGroups, group)
+		} else {
+			if len(clause.RefinedGroups) > 0 {
+				return p.errorf("ungrouped attribute at %s must precede attribute groups", p.peek())
+			}
+			r, err := p.parseAttribute()
+			if err != nil {
+				return err
+			}
+			clause.Refinements = append(clause.Refinements, r)
+		}
+		if !p.accept(",") && !p.next("{") {
+			return nil
+		}
+	}
+}
+
+// parseAttribute parses an attribute-value pair
+func (p *parser) parseAttribute() (*snomed.Expression_Refinement, error) {
+	attribute, err := p.parseConcept()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("="); err != nil {
+		return nil, err
+	}
+	r := &snomed.Expression_Refinement{Attribute: attribute}
+	t := p.peek()
+	switch {
+	case p.accept("("):
+		nested, err := p.parseSubExpression()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		r.Value = &snomed.Expression_Refinement_ExpressionValue{ExpressionValue: nested}
+	case !p.done() && t.kind == stringToken:
+		p.pos++
+		r.Value = &snomed.Expression_Refinement_StringValue{StringValue: t.text}
+	case !p.done() && t.kind == numberToken:
+		p.pos++
+		if strings.Contains(t.text, ".") {
+			v, err := strconv.ParseFloat(t.text, 64)
+			if err != nil {
+				return nil, p.errorf("invalid decimal %s", t)
+			}
+			r.Value = &snomed.Expression_Refinement_DoubleValue{DoubleValue: v}
+		} else {
+			v, err := strconv.ParseInt(t.text, 10, 64)
+			if err != nil {
+				return nil, p.errorf("invalid integer %s", t)
+			}
+			r.Value = &snomed.Expression_Refinement_IntValue{IntValue: v}
+		}
+	default:
+		c, err := p.parseConcept()
+		if err != nil {
+			return nil, err
+		}
+		r.Value = &snomed.Expression_Refinement_ConceptValue{ConceptValue: c}
+	}
+	return r, nil
+}
+
+// parseConcept parses a concept identifier; any term following it has already been discarded
+func (p *parser) parseConcept() (*snomed.Concept, error) {
+	t := p.peek()
+	if p.done() || t.kind != conceptToken {
+		return nil, p.errorf("expected concept identifier, got %s", t)
+	}
+	p.pos++
+	id, err := snomed.ParseValidIdentifier(t.text, true)
+	if err != nil || !id.IsConcept() {
+		return nil, p.errorf("invalid concept identifier %s", t)
+	}
+	return &snomed.Concept{Id: id.Integer()}, nil
+}
+
+// String returns the expression written in the compositional grammar, without terms
+func String(e *snomed.Expression) string {
+	var b strings.Builder
+	if e.DefinitionStatus == snomed.Expression_SUBTYPE_OF {
+		b.WriteString("<<< ")
+	}
+	writeSubExpression(&b, e)
+	return b.String()
+}
+
+func writeSubExpression(b *strings.Builder, e *snomed.Expression) {
+	var refinements []*snomed.Expression_Refinement
+	var groups []*snomed.Expression_RefinementGroup
+	for i, clause := range e.Terms {
+		if i > 0 {
+			b.WriteString(" + ")
+		}
+		b.WriteString(strconv.FormatInt(clause.GetConcept().GetId(), 10))
+		refinements = append(refinements, clause.Refinements...)
+		groups = append(groups, clause.RefinedGroups...)
+	}
+	if len(refinements) == 0 && len(groups) == 0 {
+		return
+	}
+	b.WriteString(" : ")
+	writeAttributes(b, refinements)
+	for i, group := range groups {
+		if i > 0 || len(refinements) > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("{ ")
+		writeAttributes(b, group.Refinement)
+		b.WriteString(" }")
+	}
+}
+
+func writeAttributes(b *strings.Builder, refinements []*snomed.Expression_Refinement) {
+	for i, r := range refinements {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.FormatInt(r.GetAttribute().GetId(), 10))
+		b.WriteString(" = ")
+		switch v := r.Value.(type) {
+		case *snomed.Expression_Refinement_ConceptValue:
+			b.WriteString(strconv.FormatInt(v.ConceptValue.GetId(), 10))
+		case *snomed.Expression_Refinement_ExpressionValue:
+			b.WriteString("(")
+			writeSubExpression(b, v.ExpressionValue)
+			b.WriteString(")")
+		case *snomed.Expression_Refinement_StringValue:
+			b.WriteString(`"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(v.StringValue) + `"`)
+		case *snomed.Expression_Refinement_IntValue:
+			b.WriteString("#" + strconv.FormatInt(v.IntValue, 10))
+		case *snomed.Expression_Refinement_DoubleValue:
+			s := strconv.FormatFloat(v.DoubleValue, 'f', -1, 64)
+			if !strings.Contains(s, ".") {
+				s += ".0"
+			}
+			b.WriteString("#" + s)
+		}
+	}
+}
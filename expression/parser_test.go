@@ -0,0 +1,59 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string // the expression written without terms, or as written if empty
+	}{
+		{"71388002 |Procedure| : 405813007 |Procedure site| = 15497006 |Ovarian structure|", "71388002 : 405813007 = 15497006"},
+		{"=== 24700007", "24700007"},
+		{"<<< 24700007 + 6118003", ""},
+		{"71388002 : { 260686004 = 129304002, 405813007 = 15497006 }, { 260686004 = 129304002, 405813007 = 31435000 }", ""},
+		{"71388002:{260686004=129304002}{260686004=129304002}", "71388002 : { 260686004 = 129304002 }, { 260686004 = 129304002 }"},
+		{"71388002 : 272741003 = 7771000, { 260686004 = 129304002 }", ""},
+		{"71388002 : 405813007 = (15497006 : 272741003 = 7771000)", ""},
+		{`373873005 : 1142135004 = #250, 1142138002 = #0.5, 774159003 = "Acme \"Tablets\""`, ""},
+	}
+	for _, test := range tests {
+		e, err := Parse(test.expression)
+		if err != nil {
+			t.Errorf("failed to parse '%s': %s", test.expression, err)
+			continue
+		}
+		expected := test.expected
+		if expected == "" {
+			expected = test.expression
+		}
+		if s := String(e); s != expected {
+			t.Errorf("expected '%s', got '%s'", expected, s)
+		}
+	}
+	e, err := Parse("<<< 71388002 : 1142135004 = #250")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.DefinitionStatus != snomed.Expression_SUBTYPE_OF || e.Terms[0].Refinements[0].GetIntValue() != 250 {
+		t.Errorf("expression not parsed as expected: %v", e)
+	}
+	invalid := []string{
+		"",
+		"71388002 :",
+		"71388002 : 405813007",
+		"71388002 : 405813007 = (15497006",
+		"71388002 : { 405813007 = 15497006 }, 260686004 = 129304002",
+		"71388001",
+		"1001016 |A description|",
+		`71388002 : 774159003 = "unterminated`,
+	}
+	for _, s := range invalid {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected error parsing '%s'", s)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package expression
+
+import (
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/storage"
+)
+
+// Resolve replaces each concept of the expression with the concept from the store,
+// returning an error if any concept does not exist.
+func Resolve(store storage.Store, e *snomed.Expression) error {
+	for _, clause := range e.Terms {
+		if err := resolve(store, &clause.Concept); err != nil {
+			return err
+		}
+		if err := resolveRefinements(store, clause.Refinements); err != nil {
+			return err
+		}
+		for _, group := range clause.RefinedGroups {
+			if err := resolveRefinements(store, group.Refinement); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func resolveRefinements(store storage.Store, refinements []*snomed.Expression_Refinement) error {
+	for _, r := range refinements {
+		if err := resolve(store, &r.Attribute); err != nil {
+			return err
+		}
+		switch v := r.Value.(type) {
+		case *snomed.Expression_Refinement_ConceptValue:
+			if err := resolve(store, &v.ConceptValue); err != nil {
+				return err
+			}
+		case *snomed.Expression_Refinement_ExpressionValue:
+			if err := Resolve(store, v.ExpressionValue); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve replaces the concept with that from the store
+func resolve(store storage.Store, c **snomed.Concept) error {
+	concept, err := store.GetConcept((*c).GetId())
+	if err != nil {
+		return err
+	}
+	*c = concept
+	return nil
+}
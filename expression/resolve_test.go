@@ -0,0 +1,34 @@
+package expression_test
+
+import (
+	"testing"
+
+	"github.com/wardle/go-terminology/expression"
+	"github.com/wardle/go-terminology/terminology"
+	"github.com/wardle/go-terminology/terminology/storage"
+	"github.com/wardle/go-terminology/terminology/terminologytest"
+)
+
+// TestResolve resolves the concepts of expressions against a tiny release
+func TestResolve(t *testing.T) {
+	svc := terminologytest.New(t, terminology.Options{InMemoryIndex: true})
+	defer svc.Close()
+
+	e, err := expression.Parse("64572001 |Disease| : { 363698007 |Finding site| = 272673000 |Bone structure| }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := expression.Resolve(svc.Store, e); err != nil {
+		t.Fatal(err)
+	}
+	if value := e.Terms[0].RefinedGroups[0].Refinement[0].GetConceptValue(); value == nil || !value.Active {
+		t.Errorf("concept not resolved: %v", value)
+	}
+	e, err = expression.Parse("64572001 : 363698007 = 15497006 |Ovarian structure|")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := expression.Resolve(svc.Store, e); !storage.IsNotFound(err) {
+		t.Errorf("expected concept not found, got %v", err)
+	}
+}
@@ -0,0 +1,618 @@
+// Package metaphone provides an implementation of the Double Metaphone phonetic encoding algorithm.
+//
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+// Double Metaphone was described by Lawrence Philips in "The Double Metaphone Search Algorithm",
+// C/C++ Users Journal, June 2000. This implementation follows the rules of the widely used
+// Apache Commons Codec implementation, returning a primary encoding and an alternate encoding
+// that accounts for words of different origins, such as "Smith" and "Schmidt".
+package metaphone
+
+import (
+	"strings"
+)
+
+// DefaultLength is the conventional maximum length of an encoding
+const DefaultLength = 4
+
+// DoubleMetaphone returns the primary and alternate encodings of the word specified, each
+// of at most maxLength characters. The encodings are the same for most words.
+func DoubleMetaphone(word string, maxLength int) (string, string) {
+	e := &encoder{
+		value:     []rune(strings.ToUpper(strings.TrimSpace(word))),
+		maxLength: maxLength,
+	}
+	if len(e.value) == 0 {
+		return "", ""
+	}
+	e.slavoGermanic = e.isSlavoGermanic()
+	e.encode()
+	return e.primary.String(), e.alternate.String()
+}
+
+var silentStarts = []string{"GN", "KN", "PN", "WR", "PS"}
+
+type encoder struct {
+	value              []rune
+	maxLength          int
+	slavoGermanic      bool
+	primary, alternate strings.Builder
+}
+
+func (e *encoder) encode() {
+	index := 0
+	for _, s := range silentStarts {
+		if e.contains(0, 2, s) {
+			index = 1
+		}
+	}
+	for !e.complete() && index < len(e.value) {
+		switch e.at(index) {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if index == 0 {
+				e.append("A")
+			}
+			index++
+		case 'B':
+			e.append("P")
+			index = e.skipDouble(index, 'B')
+		case 'Ç':
+			e.append("S")
+			index++
+		case 'C':
+			index = e.handleC(index)
+		case 'D':
+			index = e.handleD(index)
+		case 'F':
+			e.append("F")
+			index = e.skipDouble(index, 'F')
+		case 'G':
+			index = e.handleG(index)
+		case 'H':
+			index = e.handleH(index)
+		case 'J':
+			index = e.handleJ(index)
+		case 'K':
+			e.append("K")
+			index = e.skipDouble(index, 'K')
+		case 'L':
+			index = e.handleL(index)
+		case 'M':
+			e.append("M")
+			if e.conditionM0(index) {
+				index += 2
+			} else {
+				index++
+			}
+		case 'N':
+			e.append("N")
+			index = e.skipDouble(index, 'N')
+		case 'Ñ':
+			e.append("N")
+			index++
+		case 'P':
+			index = e.handleP(index)
+		case 'Q':
+			e.append("K")
+			index = e.skipDouble(index, 'Q')
+		case 'R':
+			index = e.handleR(index)
+		case 'S':
+			index = e.handleS(index)
+		case 'T':
+			index = e.handleT(index)
+		case 'V':
+			e.append("F")
+			index = e.skipDouble(index, 'V')
+		case 'W':
+			index = e.handleW(index)
+		case 'X':
+			index = e.handleX(index)
+		case 'Z':
+			index = e.handleZ(index)
+		default:
+			index++
+		}
+	}
+}
+
+func (e *encoder) handleC(index int) int {
+	switch {
+	case e.conditionC0(index):
+		e.append("K")
+		return index + 2
+	case index == 0 && e.contains(index, 6, "CAESAR"):
+		e.append("S")
+		return index + 2
+	case e.contains(index, 2, "CH"):
+		return e.handleCH(index)
+	case e.contains(index, 2, "CZ") && !e.contains(index-2, 4, "WICZ"):
+		// "Czerny"
+		e.appendBoth("S", "X")
+		return index + 2
+	case e.contains(index+1, 3, "CIA"):
+		// "focaccia"
+		e.append("X")
+		return index + 3
+	case e.contains(index, 2, "CC") && !(index == 1 && e.at(0) == 'M'):
+		// double "cc" but not "McClelland"
+		return e.handleCC(index)
+	case e.contains(index, 2, "CK", "CG", "CQ"):
+		e.append("K")
+		return index + 2
+	case e.contains(index, 2, "CI", "CE", "CY"):
+		// Italian vs. English
+		if e.contains(index, 3, "CIO", "CIE", "CIA") {
+			e.appendBoth("S", "X")
+		} else {
+			e.append("S")
+		}
+		return index + 2
+	}
+	e.append("K")
+	switch {
+	case e.contains(index+1, 2, " C", " Q", " G"):
+		// "Mac Caffrey", "Mac Gregor"
+		return index + 3
+	case e.contains(index+1, 1, "C", "K", "Q") && !e.contains(index+1, 2, "CE", "CI"):
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *encoder) handleCC(index int) int {
+	if e.contains(index+2, 1, "I", "E", "H") && !e.contains(index+2, 2, "HU") {
+		// "bellocchio" but not "bacchus"
+		if (index == 1 && e.at(index-1) == 'A') || e.contains(index-1, 5, "UCCEE", "UCCES") {
+			// "accident", "accede", "succeed"
+			e.append("KS")
+		} else {
+			// "bacci", "bertucci", other Italian
+			e.append("X")
+		}
+		return index + 3
+	}
+	// Pierce's rule
+	e.append("K")
+	return index + 2
+}
+
+func (e *encoder) handleCH(index int) int {
+	switch {
+	case index > 0 && e.contains(index, 4, "CHAE"):
+		// "Michael"
+		e.appendBoth("K", "X")
+	case e.conditionCH0(index), e.conditionCH1(index):
+		// Greek roots, such as "chemistry" and "chorus", and Germanic 'ch' for 'kh' sound
+		e.append("K")
+	case index > 0 && e.contains(0, 2, "MC"):
+		e.append("K")
+	case index > 0:
+		e.appendBoth("X", "K")
+	default:
+		e.append("X")
+	}
+	return index + 2
+}
+
+func (e *encoder) handleD(index int) int {
+	switch {
+	case e.contains(index, 2, "DG"):
+		if e.contains(index+2, 1, "I", "E", "Y") {
+			// "edge"
+			e.append("J")
+			return index + 3
+		}
+		// "Edgar"
+		e.append("TK")
+		return index + 2
+	case e.contains(index, 2, "DT", "DD"):
+		e.append("T")
+		return index + 2
+	}
+	e.append("T")
+	return index + 1
+}
+
+func (e *encoder) handleG(index int) int {
+	switch {
+	case e.at(index+1) == 'H':
+		return e.handleGH(index)
+	case e.at(index+1) == 'N':
+		switch {
+		case index == 1 && isVowel(e.at(0)) && !e.slavoGermanic:
+			e.appendBoth("KN", "N")
+		case !e.contains(index+2, 2, "EY") && e.at(index+1) != 'Y' && !e.slavoGermanic:
+			e.appendBoth("N", "KN")
+		default:
+			e.append("KN")
+		}
+		return index + 2
+	case e.contains(index+1, 2, "LI") && !e.slavoGermanic:
+		e.appendBoth("KL", "L")
+		return index + 2
+	case index == 0 && (e.at(index+1) == 'Y' || e.contains(index+1, 2, "ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER")):
+		// -ges-, -gep-, -gel-, -gie- at beginning
+		e.appendBoth("K", "J")
+		return index + 2
+	case (e.contains(index+1, 2, "ER") || e.at(index+1) == 'Y') &&
+		!e.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!e.contains(index-1, 1, "E", "I") &&
+		!e.contains(index-1, 3, "RGY", "OGY"):
+		// -ger-, -gy-
+		e.appendBoth("K", "J")
+		return index + 2
+	case e.contains(index+1, 1, "E", "I", "Y") || e.contains(index-1, 4, "AGGI", "OGGI"):
+		// Italian "biaggi"
+		switch {
+		case e.contains(0, 4, "VAN ", "VON ") || e.contains(0, 3, "SCH") || e.contains(index+1, 2, "ET"):
+			// obvious Germanic
+			e.append("K")
+		case e.contains(index+1, 3, "IER"):
+			e.append("J")
+		default:
+			e.appendBoth("J", "K")
+		}
+		return index + 2
+	case e.at(index+1) == 'G':
+		e.append("K")
+		return index + 2
+	}
+	e.append("K")
+	return index + 1
+}
+
+func (e *encoder) handleGH(index int) int {
+	switch {
+	case index > 0 && !isVowel(e.at(index-1)):
+		e.append("K")
+	case index == 0:
+		if e.at(index+2) == 'I' {
+			e.append("J")
+		} else {
+			e.append("K")
+		}
+	case (index > 1 && e.contains(index-2, 1, "B", "H", "D")) ||
+		(index > 2 && e.contains(index-3, 1, "B", "H", "D")) ||
+		(index > 3 && e.contains(index-4, 1, "B", "H")):
+		// Parker's rule, such as "hugh"
+	case index > 2 && e.at(index-1) == 'U' && e.contains(index-3, 1, "C", "G", "L", "R", "T"):
+		// "laugh", "McLaughlin", "cough", "gough", "rough", "tough"
+		e.append("F")
+	case index > 0 && e.at(index-1) != 'I':
+		e.append("K")
+	}
+	return index + 2
+}
+
+func (e *encoder) handleH(index int) int {
+	// only keep if first and before a vowel, or between two vowels
+	if (index == 0 || isVowel(e.at(index-1))) && isVowel(e.at(index+1)) {
+		e.append("H")
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *encoder) handleJ(index int) int {
+	if e.contains(index, 4, "JOSE") || e.contains(0, 4, "SAN ") {
+		// obvious Spanish, "Jose", "San Jacinto"
+		if (index == 0 && e.at(index+4) == ' ') || len(e.value) == 4 || e.contains(0, 4, "SAN ") {
+			e.append("H")
+		} else {
+			e.appendBoth("J", "H")
+		}
+		return index + 1
+	}
+	switch {
+	case index == 0:
+		e.appendBoth("J", "A")
+	case isVowel(e.at(index-1)) && !e.slavoGermanic && (e.at(index+1) == 'A' || e.at(index+1) == 'O'):
+		e.appendBoth("J", "H")
+	case index == len(e.value)-1:
+		e.appendBoth("J", "")
+	case !e.contains(index+1, 1, "L", "T", "K", "S", "N", "M", "B", "Z") && !e.contains(index-1, 1, "S", "K", "L"):
+		e.append("J")
+	}
+	return e.skipDouble(index, 'J')
+}
+
+func (e *encoder) handleL(index int) int {
+	if e.at(index+1) == 'L' {
+		if e.conditionL0(index) {
+			e.appendBoth("L", "")
+		} else {
+			e.append("L")
+		}
+		return index + 2
+	}
+	e.append("L")
+	return index + 1
+}
+
+func (e *encoder) handleP(index int) int {
+	if e.at(index+1) == 'H' {
+		e.append("F")
+		return index + 2
+	}
+	e.append("P")
+	if e.contains(index+1, 1, "P", "B") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *encoder) handleR(index int) int {
+	if index == len(e.value)-1 && !e.slavoGermanic && e.contains(index-2, 2, "IE") && !e.contains(index-4, 2, "ME", "MA") {
+		// French, such as "Rogier"
+		e.appendBoth("", "R")
+	} else {
+		e.append("R")
+	}
+	return e.skipDouble(index, 'R')
+}
+
+func (e *encoder) handleS(index int) int {
+	switch {
+	case e.contains(index-1, 3, "ISL", "YSL"):
+		// "island", "isle", "carlisle", "carlysle"
+		return index + 1
+	case index == 0 && e.contains(index, 5, "SUGAR"):
+		e.appendBoth("X", "S")
+		return index + 1
+	case e.contains(index, 2, "SH"):
+		if e.contains(index+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// Germanic
+			e.append("S")
+		} else {
+			e.append("X")
+		}
+		return index + 2
+	case e.contains(index, 3, "SIO", "SIA") || e.contains(index, 4, "SIAN"):
+		// Italian and Armenian
+		if e.slavoGermanic {
+			e.append("S")
+		} else {
+			e.appendBoth("S", "X")
+		}
+		return index + 3
+	case (index == 0 && e.contains(index+1, 1, "M", "N", "L", "W")) || e.contains(index+1, 1, "Z"):
+		// German and anglicisations, such as "smith" matching "schmidt", and Slavic -sz-
+		e.appendBoth("S", "X")
+		if e.contains(index+1, 1, "Z") {
+			return index + 2
+		}
+		return index + 1
+	case e.contains(index, 2, "SC"):
+		return e.handleSC(index)
+	}
+	if index == len(e.value)-1 && e.contains(index-2, 2, "AI", "OI") {
+		// French, such as "resnais", "artois"
+		e.appendBoth("", "S")
+	} else {
+		e.append("S")
+	}
+	if e.contains(index+1, 1, "S", "Z") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *encoder) handleSC(index int) int {
+	switch {
+	case e.at(index+2) == 'H':
+		// Schlesinger's rule
+		switch {
+		case e.contains(index+3, 2, "ER", "EN"):
+			// Dutch origin, such as "schermerhorn", "schenker"
+			e.appendBoth("X", "SK")
+		case e.contains(index+3, 2, "OO", "UY", "ED", "EM"):
+			// Dutch origin, such as "school", "schooner"
+			e.append("SK")
+		case index == 0 && !isVowel(e.at(3)) && e.at(3) != 'W':
+			e.appendBoth("X", "S")
+		default:
+			e.append("X")
+		}
+	case e.contains(index+2, 1, "I", "E", "Y"):
+		e.append("S")
+	default:
+		e.append("SK")
+	}
+	return index + 3
+}
+
+func (e *encoder) handleT(index int) int {
+	switch {
+	case e.contains(index, 4, "TION"), e.contains(index, 3, "TIA", "TCH"):
+		e.append("X")
+		return index + 3
+	case e.contains(index, 2, "TH") || e.contains(index, 3, "TTH"):
+		if e.contains(index+2, 2, "OM", "AM") || e.contains(0, 4, "VAN ", "VON ") || e.contains(0, 3, "SCH") {
+			// "thomas", "thames" or Germanic
+			e.append("T")
+		} else {
+			e.appendBoth("0", "T")
+		}
+		return index + 2
+	}
+	e.append("T")
+	if e.contains(index+1, 1, "T", "D") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *encoder) handleW(index int) int {
+	switch {
+	case e.contains(index, 2, "WR"):
+		// can also be in the middle of a word
+		e.append("R")
+		return index + 2
+	case index == 0 && (isVowel(e.at(index+1)) || e.contains(index, 2, "WH")):
+		if isVowel(e.at(index + 1)) {
+			// "Wasserman" should match "Vasserman"
+			e.appendBoth("A", "F")
+		} else {
+			// "Uomo" should match "Womo"
+			e.append("A")
+		}
+		return index + 1
+	case (index == len(e.value)-1 && isVowel(e.at(index-1))) ||
+		e.contains(index-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		e.contains(0, 3, "SCH"):
+		// "Arnow" should match "Arnoff"
+		e.appendBoth("", "F")
+		return index + 1
+	case e.contains(index, 4, "WICZ", "WITZ"):
+		// Polish, such as "filipowicz"
+		e.appendBoth("TS", "FX")
+		return index + 4
+	}
+	return index + 1
+}
+
+func (e *encoder) handleX(index int) int {
+	if index == 0 {
+		e.append("S")
+		return index + 1
+	}
+	if !(index == len(e.value)-1 && (e.contains(index-3, 3, "IAU", "EAU") || e.contains(index-2, 2, "AU", "OU"))) {
+		// not French, such as "breaux"
+		e.append("KS")
+	}
+	if e.contains(index+1, 1, "C", "X") {
+		return index + 2
+	}
+	return index + 1
+}
+
+func (e *encoder) handleZ(index int) int {
+	if e.at(index+1) == 'H' {
+		// Chinese pinyin, such as "zhao"
+		e.append("J")
+		return index + 2
+	}
+	if e.contains(index+1, 2, "ZO", "ZI", "ZA") || (e.slavoGermanic && index > 0 && e.at(index-1) != 'T') {
+		e.appendBoth("S", "TS")
+	} else {
+		e.append("S")
+	}
+	return e.skipDouble(index, 'Z')
+}
+
+func (e *encoder) conditionC0(index int) bool {
+	switch {
+	case e.contains(index, 4, "CHIA"):
+		return true
+	case index <= 1, isVowel(e.at(index - 2)), !e.contains(index-1, 3, "ACH"):
+		return false
+	}
+	c := e.at(index + 2)
+	return (c != 'I' && c != 'E') || e.contains(index-2, 6, "BACHER", "MACHER")
+}
+
+func (e *encoder) conditionCH0(index int) bool {
+	if index != 0 {
+		return false
+	}
+	if !e.contains(index+1, 5, "HARAC", "HARIS") && !e.contains(index+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !e.contains(0, 5, "CHORE")
+}
+
+func (e *encoder) conditionCH1(index int) bool {
+	return e.contains(0, 4, "VAN ", "VON ") || e.contains(0, 3, "SCH") ||
+		e.contains(index-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		e.contains(index+2, 1, "T", "S") ||
+		((e.contains(index-1, 1, "A", "O", "U", "E") || index == 0) &&
+			(e.contains(index+2, 1, "L", "R", "N", "M", "B", "H", "F", "V", "W", " ") || index+1 == len(e.value)-1))
+}
+
+func (e *encoder) conditionL0(index int) bool {
+	if index == len(e.value)-3 && e.contains(index-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (e.contains(len(e.value)-2, 2, "AS", "OS") || e.contains(len(e.value)-1, 1, "A", "O")) &&
+		e.contains(index-1, 4, "ALLE")
+}
+
+func (e *encoder) conditionM0(index int) bool {
+	if e.at(index+1) == 'M' {
+		return true
+	}
+	return e.contains(index-1, 3, "UMB") && (index+1 == len(e.value)-1 || e.contains(index+2, 2, "ER"))
+}
+
+func (e *encoder) isSlavoGermanic() bool {
+	s := string(e.value)
+	return strings.ContainsAny(s, "WK") || strings.Contains(s, "CZ") || strings.Contains(s, "WITZ")
+}
+
+// skipDouble returns the index of the next character, skipping a repeat of the character specified
+func (e *encoder) skipDouble(index int, c rune) int {
+	if e.at(index+1) == c {
+		return index + 2
+	}
+	return index + 1
+}
+
+// at returns the character at the index specified, or zero if the index is out of range
+func (e *encoder) at(index int) rune {
+	if index < 0 || index >= len(e.value) {
+		return 0
+	}
+	return e.value[index]
+}
+
+// contains determines whether the substring of the length specified, starting at the index specified,
+// is one of the candidates.
+func (e *encoder) contains(start int, length int, candidates ...string) bool {
+	if start < 0 || start+length > len(e.value) {
+		return false
+	}
+	target := string(e.value[start : start+length])
+	for _, candidate := range candidates {
+		if target == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *encoder) append(s string) {
+	e.appendBoth(s, s)
+}
+
+func (e *encoder) appendBoth(primary string, alternate string) {
+	appendLimited(&e.primary, primary, e.maxLength)
+	appendLimited(&e.alternate, alternate, e.maxLength)
+}
+
+func appendLimited(b *strings.Builder, s string, maxLength int) {
+	if remaining := maxLength - b.Len(); remaining < len(s) {
+		s = s[:remaining]
+	}
+	b.WriteString(s)
+}
+
+func (e *encoder) complete() bool {
+	return e.primary.Len() >= e.maxLength && e.alternate.Len() >= e.maxLength
+}
+
+func isVowel(c rune) bool {
+	return strings.ContainsRune("AEIOUY", c)
+}
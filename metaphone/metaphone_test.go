@@ -0,0 +1,60 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+package metaphone
+
+import (
+	"testing"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	test(t, "Thompson", "TMPS", "TMPS")
+	test(t, "Thumb", "0M", "TM")
+	test(t, "Smith", "SM0", "XMT")
+	test(t, "Schmidt", "XMT", "SMT")
+	test(t, "Michael", "MKL", "MXL")
+	test(t, "Xavier", "SF", "SFR")
+	test(t, "Jose", "HS", "HS")
+	test(t, "Arnow", "ARN", "ARNF")
+	test(t, "Caesar", "SSR", "SSR")
+	test(t, "Chemistry", "KMST", "KMST")
+	test(t, "Knight", "NT", "NT")
+	test(t, "Laugh", "LF", "LF")
+	test(t, "accident", "AKST", "AKST")
+	test(t, "", "", "")
+}
+
+func TestSoundAlike(t *testing.T) {
+	sameSound(t, "sertraline", "certraline")
+	sameSound(t, "phenytoin", "fenytoin")
+	sameSound(t, "diarrhoea", "diarhea")
+	sameSound(t, "Ménière", "meniere")
+}
+
+func test(t *testing.T, word string, primary string, alternate string) {
+	p, a := DoubleMetaphone(word, DefaultLength)
+	if p != primary || a != alternate {
+		t.Errorf("expected %s to encode to %s/%s, got %s/%s", word, primary, alternate, p, a)
+	}
+}
+
+// soundLength is the length of encoding used by the search index (phoneticLength in terminology/search/bleve)
+const soundLength = 6
+
+func sameSound(t *testing.T, a string, b string) {
+	pa, _ := DoubleMetaphone(a, soundLength)
+	pb, _ := DoubleMetaphone(b, soundLength)
+	if pa != pb {
+		t.Errorf("expected %s (%s) to sound like %s (%s)", a, pa, b, pb)
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"errors"
+
+	"github.com/wardle/go-terminology/terminology"
+	"github.com/wardle/go-terminology/terminology/storage"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unaryErrorInterceptor maps errors returned by unary gRPC methods to gRPC status codes
+// so that clients, including those using the REST gateway, receive a meaningful response.
+func unaryErrorInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, toStatusError(err)
+}
+
+// streamErrorInterceptor maps errors returned by streaming gRPC methods to gRPC status codes
+func streamErrorInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return toStatusError(handler(srv, ss))
+}
+
+// toStatusError converts an error into a gRPC status error with an appropriate code.
+// Errors that already carry a gRPC status are returned unchanged.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case storage.IsNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case storage.IsWrongComponentType(err):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case storage.IsStoreClosed(err):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.Is(err, terminology.ErrConstraintTooBroad):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case storage.IsReadOnly(err):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return err
+}
@@ -17,7 +17,10 @@ import (
 
 func serveGRPC(l net.Listener, sct *terminology.Svc) {
 	// Register gRPC Services
-	var gRPCOpts []grpc.ServerOption
+	gRPCOpts := []grpc.ServerOption{
+		grpc.UnaryInterceptor(unaryErrorInterceptor),   // Map storage errors to gRPC status codes
+		grpc.StreamInterceptor(streamErrorInterceptor), // and for streaming methods
+	}
 	gRPCServer := grpc.NewServer(gRPCOpts...)
 	snomed.RegisterSnomedCTServer(gRPCServer, &snomedCTSrv{svc: sct}) // Register SnomedCT Service
 	snomed.RegisterSearchServer(gRPCServer, &searchSrv{svc: sct})     // Register Search Service
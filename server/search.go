@@ -1,10 +1,15 @@
 package server
 
 import (
+	"log"
+
+	"github.com/wardle/go-terminology/ecl"
 	"github.com/wardle/go-terminology/snomed"
 	"github.com/wardle/go-terminology/terminology"
 	"golang.org/x/net/context"
 	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // searchSrv implements the snomed.SearchServer gRPC interface
@@ -16,13 +21,32 @@ type searchSrv struct {
 // Search implements the Search gRPC server method returning a *snomed.SearchResponse result
 func (ss *searchSrv) Search(ctx context.Context, searchRequest *snomed.SearchRequest) (*snomed.SearchResponse, error) {
 	var output snomed.SearchResponse
-	results, err := ss.svc.Search.Search(searchRequest)
+	if _, _, err := language.ParseAcceptLanguage(searchRequest.AcceptedLanguages); err != nil {
+		return &output, status.Errorf(codes.InvalidArgument, "invalid accepted languages: %v", err)
+	}
+	if searchRequest.Ecl != "" {
+		if _, err := ecl.Parse(searchRequest.Ecl); err != nil {
+			return &output, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	result, err := ss.svc.SearchHits(ctx, searchRequest)
 	if err != nil {
 		return &output, err
 	}
+	output.TotalHits = int64(result.Total)
+	output.NextCursor = result.NextCursor
+	if result.Explanation != nil {
+		output.Explanation = &snomed.SearchResponse_Explanation{Tokens: result.Explanation.Tokens, Query: result.Explanation.Query}
+	}
+	// a spelling suggestion is computed only when requested or when the search found no exact matches
+	if searchRequest.Suggest || result.Total == 0 || result.Fuzzy {
+		if output.Suggestion, err = ss.svc.Suggest(ctx, searchRequest); err != nil {
+			log.Printf("search: failed to suggest spelling for '%s': %s", searchRequest.Search, err)
+		}
+	}
 
-	for _, v := range results {
-		description, err := ss.svc.GetDescription(v)
+	for _, hit := range result.Hits {
+		description, err := ss.svc.GetDescription(hit.DescriptionID)
 		if err != nil {
 			return &output, err
 		}
@@ -38,12 +62,41 @@ func (ss *searchSrv) Search(ctx context.Context, searchRequest *snomed.SearchReq
 			Term:          description.Term,
 			ConceptId:     description.ConceptId,
 			PreferredTerm: preferredDescription.Term,
+			Score:         hit.Score,
+			DescriptionId: description.Id,
+			Highlight:     hit.Highlight,
+			Explanation:   hit.Explanation,
 		})
 	}
 
+	tags, _, _ := language.ParseAcceptLanguage(searchRequest.AcceptedLanguages)
+	for _, facet := range result.Facets {
+		fr := &snomed.SearchResponse_FacetResult{Facet: facet.Facet}
+		for _, count := range facet.Counts {
+			fc := &snomed.SearchResponse_FacetCount{ConceptId: count.ConceptID, Count: int64(count.Count)}
+			if concept, err := ss.svc.GetConcept(count.ConceptID); err == nil {
+				fc.Name = ss.facetName(concept, tags)
+			}
+			fr.Counts = append(fr.Counts, fc)
+		}
+		output.Facets = append(output.Facets, fr)
+	}
+
 	return &output, nil
 }
 
+// facetName returns the preferred synonym for a concept counted in a facet, or its fully specified name
+// if there is no synonym in the languages requested, or an empty string if neither can be found
+func (ss *searchSrv) facetName(concept *snomed.Concept, tags []language.Tag) string {
+	if d, found, err := ss.svc.GetPreferredSynonym(concept, tags); err == nil && found {
+		return d.Term
+	}
+	if d, found, err := ss.svc.GetFullySpecifiedName(concept, tags); err == nil && found {
+		return d.Term
+	}
+	return ""
+}
+
 /*
 // Search implementation for streaming results
 func (ss *searchSrv) Search(searchRequest *snomed.SearchRequest, server snomed.Search_SearchServer) error {
@@ -2,11 +2,17 @@ package server
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 
+	"github.com/wardle/go-terminology/ecl"
+	"github.com/wardle/go-terminology/expression"
 	"github.com/wardle/go-terminology/snomed"
 	"github.com/wardle/go-terminology/terminology"
 	"golang.org/x/net/context"
 	"golang.org/x/text/language"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // snomedCTSrv implements the snomed.SnomedCTServer gRPC interface
@@ -36,7 +42,7 @@ func (ss *snomedCTSrv) GetExtendedConcept(ctx context.Context, conceptID *snomed
 		return nil, err
 	}
 	result.Relationships = relationships
-	recursiveParentIDs, err := ss.svc.GetAllParentIDs(c)
+	recursiveParentIDs, err := ss.svc.GetAllParentIDsContext(ctx, c)
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +103,10 @@ func (ss *snomedCTSrv) Translate(ctx context.Context, tr *snomed.TranslateReques
 	if err != nil {
 		return nil, err
 	}
-	generic, found := ss.svc.GenericiseTo(c, members)
+	generic, found, err := ss.svc.GenericiseToContext(ctx, c, members)
+	if err != nil {
+		return nil, err
+	}
 	if found {
 		result := snomed.TranslateResponse_Concept{}
 		result.Concept = generic
@@ -110,7 +119,11 @@ func (ss *snomedCTSrv) Translate(ctx context.Context, tr *snomed.TranslateReques
 // Subsumes determines whether code A subsumes code B, according to the definition
 // in the HL7 FHIR terminology service specification.
 // See https://www.hl7.org/fhir/terminology-service.html
+// Either code may instead be an expression, in which case subsumption is determined using the normal form of each.
 func (ss *snomedCTSrv) Subsumes(ctx context.Context, r *snomed.SubsumptionRequest) (*snomed.SubsumptionResponse, error) {
+	if r.ExpressionA != "" || r.ExpressionB != "" {
+		return ss.subsumesExpression(r)
+	}
 	res := snomed.SubsumptionResponse{}
 	if r.CodeA == r.CodeB {
 		res.Result = snomed.SubsumptionResponse_EQUIVALENT
@@ -135,3 +148,110 @@ func (ss *snomedCTSrv) Subsumes(ctx context.Context, r *snomed.SubsumptionReques
 	res.Result = snomed.SubsumptionResponse_NOT_SUBSUMED
 	return &res, nil
 }
+
+// subsumesExpression determines whether expression or code A subsumes expression or code B
+func (ss *snomedCTSrv) subsumesExpression(r *snomed.SubsumptionRequest) (*snomed.SubsumptionResponse, error) {
+	a, err := ss.subsumptionExpression(r.CodeA, r.ExpressionA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ss.subsumptionExpression(r.CodeB, r.ExpressionB)
+	if err != nil {
+		return nil, err
+	}
+	subsumes, err := expression.Subsumes(ss.svc.Store, a, b)
+	if err != nil {
+		return nil, err
+	}
+	subsumedBy, err := expression.Subsumes(ss.svc.Store, b, a)
+	if err != nil {
+		return nil, err
+	}
+	res := snomed.SubsumptionResponse{Result: snomed.SubsumptionResponse_NOT_SUBSUMED}
+	switch {
+	case subsumes && subsumedBy:
+		res.Result = snomed.SubsumptionResponse_EQUIVALENT
+	case subsumes:
+		res.Result = snomed.SubsumptionResponse_SUBSUMES
+	case subsumedBy:
+		res.Result = snomed.SubsumptionResponse_SUBSUMED_BY
+	}
+	return &res, nil
+}
+
+// subsumptionExpression returns the expression specified, or otherwise an expression for the concept specified
+func (ss *snomedCTSrv) subsumptionExpression(code int64, s string) (*snomed.Expression, error) {
+	if s == "" {
+		if code == 0 {
+			return nil, status.Error(codes.InvalidArgument, "either a code or an expression must be specified for each side of a subsumption test")
+		}
+		s = strconv.FormatInt(code, 10)
+	}
+	e, err := expression.Parse(s)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return e, expression.Resolve(ss.svc.Store, e)
+}
+
+// EvaluateConstraint returns the concepts matching an expression constraint, in identifier order
+func (ss *snomedCTSrv) EvaluateConstraint(ctx context.Context, r *snomed.ConstraintRequest) (*snomed.ConstraintResponse, error) {
+	c, err := ecl.Parse(r.Ecl)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ids, err := ecl.New(ss.svc.Store).EvaluateConstraint(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	res := &snomed.ConstraintResponse{ConceptIds: ids, Total: int64(len(ids))}
+	if r.MaximumHits > 0 && len(ids) > int(r.MaximumHits) {
+		res.ConceptIds = ids[:r.MaximumHits]
+	}
+	return res, nil
+}
+
+// ParseExpression parses an expression written in the SNOMED-CT compositional grammar,
+// returning an error if it is invalid or any of its concepts do not exist
+func (ss *snomedCTSrv) ParseExpression(ctx context.Context, r *snomed.ExpressionRequest) (*snomed.Expression, error) {
+	e, err := expression.Parse(r.Expression)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := expression.Resolve(ss.svc.Store, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetStatistics returns summary statistics for the datastore
+func (ss *snomedCTSrv) GetStatistics(ctx context.Context, r *snomed.StatisticsRequest) (*snomed.Statistics, error) {
+	stats, err := ss.svc.GetStatistics()
+	if err != nil {
+		return nil, err
+	}
+	return &snomed.Statistics{
+		Concepts:                int64(stats.Concepts),
+		ActiveConcepts:          int64(stats.ActiveConcepts),
+		Descriptions:            int64(stats.Descriptions),
+		ActiveDescriptions:      int64(stats.ActiveDescriptions),
+		Relationships:           int64(stats.Relationships),
+		ActiveRelationships:     int64(stats.ActiveRelationships),
+		ReferenceSetItems:       int64(stats.RefsetItems),
+		ActiveReferenceSetItems: int64(stats.ActiveRefsetItems),
+		Modules:                 statisticsCounts(stats.Modules, stats.Names),
+		Hierarchies:             statisticsCounts(stats.Hierarchies, stats.Names),
+		LanguageReferenceSets:   statisticsCounts(stats.LanguageRefsets, stats.Names),
+		ReferenceSets:           statisticsCounts(stats.RefsetMembers, stats.Names),
+	}, nil
+}
+
+// statisticsCounts converts a map of counts into named counts, ordered by identifier
+func statisticsCounts(counts map[int64]int, names map[int64]string) []*snomed.Statistics_Count {
+	result := make([]*snomed.Statistics_Count, 0, len(counts))
+	for id, count := range counts {
+		result = append(result, &snomed.Statistics_Count{ConceptId: id, Name: names[id], Count: int64(count)})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ConceptId < result[j].ConceptId })
+	return result
+}
@@ -69,34 +69,39 @@ func init() {
 func init() { proto.RegisterFile("server.proto", fileDescriptor_ad098daeda4239f7) }
 
 var fileDescriptor_ad098daeda4239f7 = []byte{
-	// 417 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x93, 0xdf, 0xee, 0xd2, 0x30,
-	0x14, 0xc7, 0x33, 0xff, 0x2c, 0x3f, 0x1b, 0x94, 0x78, 0x08, 0x0a, 0x43, 0x0d, 0x59, 0x8c, 0xa2,
-	0xe2, 0x2a, 0x18, 0x5f, 0x00, 0x30, 0xc4, 0x3b, 0xc3, 0x88, 0x17, 0xc6, 0x44, 0xc7, 0x76, 0xc4,
-	0x26, 0x5b, 0x3b, 0xdb, 0x42, 0x30, 0x86, 0x1b, 0x5f, 0xc1, 0x47, 0xf3, 0x15, 0x7c, 0x0f, 0x4d,
-	0xba, 0x6e, 0x0e, 0x82, 0xe1, 0x77, 0xd5, 0x9e, 0xf3, 0xed, 0x3e, 0x9f, 0xb5, 0xc9, 0x21, 0x0d,
-	0x85, 0x72, 0x8b, 0x32, 0xc8, 0xa5, 0xd0, 0x02, 0x5c, 0xc5, 0x45, 0x86, 0x89, 0xd7, 0x28, 0xd6,
-	0xa2, 0xeb, 0xdd, 0x5b, 0x0b, 0xb1, 0x4e, 0x91, 0x46, 0x39, 0xa3, 0x11, 0xe7, 0x42, 0x47, 0x9a,
-	0x09, 0xae, 0x8a, 0xd4, 0x7f, 0x4c, 0xae, 0x87, 0xb1, 0x7e, 0x33, 0x83, 0x07, 0x84, 0xb0, 0x04,
-	0xb9, 0x66, 0x9f, 0x19, 0xca, 0x8e, 0xd3, 0x77, 0x06, 0x57, 0x17, 0xb5, 0xce, 0xf8, 0xcf, 0x35,
-	0x72, 0x11, 0x1a, 0xee, 0x74, 0x09, 0xef, 0x08, 0x99, 0xa3, 0x9e, 0x0a, 0x1e, 0x63, 0xae, 0xe1,
-	0x66, 0x60, 0x85, 0x86, 0xe4, 0x35, 0xcb, 0xd2, 0xe6, 0xfe, 0xe0, 0xc7, 0xaf, 0xdf, 0x3f, 0xaf,
-	0xf8, 0xd0, 0xa7, 0xdb, 0x11, 0x2d, 0x32, 0x1a, 0x17, 0x99, 0xa2, 0xdf, 0xff, 0x39, 0xf6, 0x20,
-	0x08, 0xcc, 0x51, 0xbf, 0xde, 0x69, 0xe4, 0x09, 0x26, 0xff, 0xe1, 0xdf, 0x2d, 0xcb, 0xa3, 0x73,
-	0xfe, 0xc8, 0x78, 0x9e, 0xc1, 0x93, 0x73, 0x1e, 0x8a, 0xf6, 0x4b, 0xe0, 0xa4, 0x39, 0x47, 0x3d,
-	0x43, 0x15, 0x4b, 0x96, 0x9b, 0x77, 0x39, 0xb6, 0xb5, 0xca, 0xb2, 0x76, 0xc8, 0x7f, 0x65, 0x4c,
-	0x14, 0x9e, 0x9f, 0x35, 0x25, 0x35, 0xf4, 0x0b, 0x07, 0x56, 0xe4, 0xd6, 0xa1, 0xef, 0x52, 0xba,
-	0xa1, 0xd1, 0x3d, 0x82, 0x87, 0x35, 0x5d, 0x1d, 0x7c, 0xf8, 0x88, 0x9a, 0xdc, 0x58, 0xca, 0x88,
-	0xab, 0x34, 0xd2, 0x08, 0x9d, 0x92, 0x57, 0xb5, 0x16, 0xf8, 0x75, 0x83, 0x4a, 0x7b, 0xdd, 0x13,
-	0x89, 0xca, 0x05, 0x57, 0xe8, 0x8f, 0x8d, 0x6f, 0x08, 0x4f, 0x4f, 0x5e, 0xcf, 0xee, 0x3e, 0xb2,
-	0x64, 0x4f, 0x75, 0x25, 0xfa, 0x44, 0x2e, 0xc2, 0xcd, 0x4a, 0x6d, 0x32, 0x54, 0xe0, 0x55, 0x77,
-	0x32, 0x1d, 0xf3, 0x93, 0xa5, 0xb6, 0x77, 0x32, 0xb3, 0xe2, 0x9e, 0x11, 0xb7, 0xa1, 0x55, 0x13,
-	0x2b, 0x4b, 0x1d, 0x7f, 0x20, 0x6e, 0x88, 0x91, 0x8c, 0xbf, 0xc0, 0xa2, 0xda, 0xb5, 0x2b, 0x9a,
-	0xa9, 0x4b, 0xc9, 0x9d, 0xe3, 0xb6, 0xe5, 0x77, 0x0d, 0xbf, 0x05, 0xb7, 0xeb, 0x7c, 0x73, 0x64,
-	0x32, 0x22, 0xf7, 0x63, 0x91, 0x05, 0x98, 0x26, 0x92, 0xed, 0x02, 0x8d, 0x32, 0x63, 0x5c, 0xa4,
-	0x62, 0xfd, 0x2d, 0x28, 0x66, 0x6c, 0xe2, 0x86, 0x66, 0x7d, 0xeb, 0xbc, 0xb7, 0x73, 0xb6, 0x72,
-	0xcd, 0x08, 0xbd, 0xfc, 0x1b, 0x00, 0x00, 0xff, 0xff, 0xf3, 0xfd, 0x08, 0x11, 0x86, 0x03, 0x00,
-	0x00,
+	// 510 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x94, 0xdf, 0x6e, 0xd3, 0x30,
+	0x14, 0xc6, 0x55, 0x10, 0x65, 0x58, 0xfb, 0x03, 0xa7, 0xea, 0xb6, 0x66, 0x0c, 0x4d, 0x11, 0x82,
+	0x01, 0xa3, 0xa1, 0x45, 0xbc, 0xc0, 0xba, 0xaa, 0xe2, 0x6e, 0x6a, 0x26, 0x2e, 0x10, 0x68, 0xa4,
+	0xc9, 0xa1, 0x58, 0x4a, 0xed, 0x60, 0x9f, 0x56, 0x45, 0x68, 0x37, 0xbc, 0x02, 0xaf, 0xc0, 0x1b,
+	0xf1, 0x0a, 0x3c, 0x08, 0x9a, 0x13, 0x27, 0x5e, 0x17, 0x34, 0xae, 0x92, 0xf3, 0x7d, 0xee, 0xef,
+	0xd7, 0xb8, 0xa9, 0xd9, 0xba, 0x46, 0xb5, 0x40, 0xd5, 0xcd, 0x94, 0x24, 0x09, 0x4d, 0x2d, 0xe4,
+	0x0c, 0x13, 0x6f, 0x3d, 0xbf, 0xe6, 0xa9, 0xf7, 0x70, 0x2a, 0xe5, 0x34, 0xc5, 0x20, 0xca, 0x78,
+	0x10, 0x09, 0x21, 0x29, 0x22, 0x2e, 0x85, 0xce, 0x5b, 0xff, 0x29, 0xbb, 0x13, 0xc6, 0xf4, 0xf6,
+	0x04, 0x1e, 0x31, 0xc6, 0x13, 0x14, 0xc4, 0x3f, 0x73, 0x54, 0xbb, 0x8d, 0x83, 0xc6, 0xe1, 0xed,
+	0xb1, 0x93, 0xf4, 0x7f, 0xdd, 0x65, 0x6b, 0xa1, 0xe1, 0x0e, 0xce, 0xe0, 0x1d, 0x63, 0x23, 0xa4,
+	0x81, 0x14, 0x31, 0x66, 0x04, 0x1b, 0xdd, 0x42, 0x68, 0x48, 0xde, 0x96, 0x1d, 0x8b, 0xde, 0x3f,
+	0xfc, 0xf1, 0xfb, 0xcf, 0xcf, 0x5b, 0x3e, 0x1c, 0x04, 0x8b, 0x5e, 0x90, 0x77, 0x41, 0x9c, 0x77,
+	0x3a, 0xf8, 0x5e, 0x39, 0x2e, 0x40, 0x32, 0x18, 0x21, 0x0d, 0x97, 0x84, 0x22, 0xc1, 0xe4, 0x1f,
+	0xfc, 0x1d, 0x3b, 0xae, 0xac, 0xf3, 0x7b, 0xc6, 0xf3, 0x02, 0x9e, 0xdd, 0xe4, 0x09, 0xb0, 0xf8,
+	0x24, 0x08, 0xb6, 0x35, 0x42, 0x3a, 0x41, 0x1d, 0x2b, 0x9e, 0x99, 0x7d, 0x59, 0xb5, 0xb5, 0xec,
+	0xe8, 0x2c, 0xf2, 0xdf, 0x18, 0x53, 0x00, 0x2f, 0x6f, 0x34, 0x25, 0x0e, 0xfa, 0x55, 0x03, 0x26,
+	0x6c, 0xf3, 0xaa, 0xef, 0xbf, 0x74, 0x47, 0x46, 0xf7, 0x04, 0x1e, 0x3b, 0x3a, 0x17, 0x7c, 0x75,
+	0x13, 0x89, 0xdd, 0x3b, 0x53, 0x91, 0xd0, 0x69, 0x44, 0x08, 0xbb, 0x96, 0x57, 0x46, 0x63, 0xfc,
+	0x3a, 0x47, 0x4d, 0x5e, 0xa7, 0xa6, 0xd1, 0x99, 0x14, 0x1a, 0xfd, 0xbe, 0xf1, 0x1d, 0xc1, 0xf3,
+	0xda, 0xc7, 0x2b, 0xee, 0xce, 0x79, 0x72, 0x11, 0x50, 0x29, 0xfa, 0xc4, 0xd6, 0xc2, 0xf9, 0x44,
+	0xcf, 0x67, 0xa8, 0xc1, 0x2b, 0x9f, 0xc9, 0x24, 0xe6, 0x4b, 0x5a, 0xed, 0x5e, 0x6d, 0x57, 0x88,
+	0xf7, 0x8c, 0xb8, 0x0d, 0x2d, 0x47, 0xac, 0x2d, 0x35, 0x66, 0x30, 0x5c, 0x44, 0xe9, 0x3c, 0x22,
+	0x1c, 0x48, 0xa1, 0x49, 0x45, 0x5c, 0x10, 0x74, 0x9c, 0xb7, 0xad, 0xc8, 0xac, 0xca, 0xab, 0xab,
+	0x0a, 0xd3, 0xb6, 0x31, 0xdd, 0x87, 0x4d, 0xc7, 0x84, 0x71, 0x0a, 0xe7, 0x6c, 0xeb, 0x34, 0x52,
+	0x1a, 0x87, 0xcb, 0x4c, 0xa1, 0xd6, 0x97, 0xbf, 0x50, 0xa7, 0x7a, 0xdf, 0x6c, 0x66, 0x0d, 0x70,
+	0xbd, 0xf2, 0xf7, 0x0d, 0x79, 0x07, 0xda, 0x2e, 0xb9, 0xa2, 0x7d, 0x64, 0x1b, 0x23, 0xa4, 0xf0,
+	0xf2, 0x5f, 0xa8, 0x89, 0xc7, 0xba, 0xc2, 0x57, 0xd9, 0x35, 0x7c, 0x55, 0xd5, 0xe2, 0x75, 0x59,
+	0xf7, 0x3f, 0xb0, 0x66, 0x88, 0x91, 0x8a, 0xbf, 0xc0, 0xb8, 0xbc, 0x6b, 0x97, 0x18, 0x33, 0x5b,
+	0xfa, 0xf6, 0x6a, 0x5c, 0x6c, 0x4d, 0xc7, 0x18, 0x5a, 0xf0, 0xc0, 0x35, 0x98, 0x25, 0xc7, 0x3d,
+	0xb6, 0x1f, 0xcb, 0x59, 0x17, 0xd3, 0x44, 0xf1, 0x65, 0x97, 0x50, 0xcd, 0xb8, 0x90, 0xa9, 0x9c,
+	0x7e, 0xeb, 0xe6, 0x07, 0xd1, 0x71, 0x33, 0x34, 0xd7, 0xd3, 0xc6, 0xfb, 0xe2, 0x30, 0x9a, 0x34,
+	0xcd, 0x39, 0xf3, 0xfa, 0xef, 0x00, 0x8c, 0xe3, 0x0e, 0x30, 0xab, 0x04, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -120,6 +125,9 @@ type SnomedCTClient interface {
 	// This is an implementation of the HL7 FHIR terminology service subsumes method
 	// (https://www.hl7.org/fhir/terminology-service.html)
 	Subsumes(ctx context.Context, in *SubsumptionRequest, opts ...grpc.CallOption) (*SubsumptionResponse, error)
+	EvaluateConstraint(ctx context.Context, in *ConstraintRequest, opts ...grpc.CallOption) (*ConstraintResponse, error)
+	ParseExpression(ctx context.Context, in *ExpressionRequest, opts ...grpc.CallOption) (*Expression, error)
+	GetStatistics(ctx context.Context, in *StatisticsRequest, opts ...grpc.CallOption) (*Statistics, error)
 }
 
 type snomedCTClient struct {
@@ -207,6 +215,33 @@ func (c *snomedCTClient) Subsumes(ctx context.Context, in *SubsumptionRequest, o
 	return out, nil
 }
 
+func (c *snomedCTClient) EvaluateConstraint(ctx context.Context, in *ConstraintRequest, opts ...grpc.CallOption) (*ConstraintResponse, error) {
+	out := new(ConstraintResponse)
+	err := c.cc.Invoke(ctx, "/snomed.SnomedCT/EvaluateConstraint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snomedCTClient) ParseExpression(ctx context.Context, in *ExpressionRequest, opts ...grpc.CallOption) (*Expression, error) {
+	out := new(Expression)
+	err := c.cc.Invoke(ctx, "/snomed.SnomedCT/ParseExpression", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *snomedCTClient) GetStatistics(ctx context.Context, in *StatisticsRequest, opts ...grpc.CallOption) (*Statistics, error) {
+	out := new(Statistics)
+	err := c.cc.Invoke(ctx, "/snomed.SnomedCT/GetStatistics", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SnomedCTServer is the server API for SnomedCT service.
 type SnomedCTServer interface {
 	GetConcept(context.Context, *SctID) (*Concept, error)
@@ -218,6 +253,9 @@ type SnomedCTServer interface {
 	// This is an implementation of the HL7 FHIR terminology service subsumes method
 	// (https://www.hl7.org/fhir/terminology-service.html)
 	Subsumes(context.Context, *SubsumptionRequest) (*SubsumptionResponse, error)
+	EvaluateConstraint(context.Context, *ConstraintRequest) (*ConstraintResponse, error)
+	ParseExpression(context.Context, *ExpressionRequest) (*Expression, error)
+	GetStatistics(context.Context, *StatisticsRequest) (*Statistics, error)
 }
 
 func RegisterSnomedCTServer(s *grpc.Server, srv SnomedCTServer) {
@@ -335,6 +373,60 @@ func _SnomedCT_Subsumes_Handler(srv interface{}, ctx context.Context, dec func(i
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SnomedCT_EvaluateConstraint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConstraintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnomedCTServer).EvaluateConstraint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snomed.SnomedCT/EvaluateConstraint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnomedCTServer).EvaluateConstraint(ctx, req.(*ConstraintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SnomedCT_ParseExpression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnomedCTServer).ParseExpression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snomed.SnomedCT/ParseExpression",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnomedCTServer).ParseExpression(ctx, req.(*ExpressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SnomedCT_GetStatistics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatisticsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SnomedCTServer).GetStatistics(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/snomed.SnomedCT/GetStatistics",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SnomedCTServer).GetStatistics(ctx, req.(*StatisticsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _SnomedCT_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "snomed.SnomedCT",
 	HandlerType: (*SnomedCTServer)(nil),
@@ -359,6 +451,18 @@ var _SnomedCT_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Subsumes",
 			Handler:    _SnomedCT_Subsumes_Handler,
 		},
+		{
+			MethodName: "EvaluateConstraint",
+			Handler:    _SnomedCT_EvaluateConstraint_Handler,
+		},
+		{
+			MethodName: "ParseExpression",
+			Handler:    _SnomedCT_ParseExpression_Handler,
+		},
+		{
+			MethodName: "GetStatistics",
+			Handler:    _SnomedCT_GetStatistics_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -21,6 +21,33 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+type Expression_DefinitionStatus int32
+
+const (
+	// the expression is equivalent to the concept it defines ("===")
+	Expression_EQUIVALENT_TO Expression_DefinitionStatus = 0
+	// the expression is a supertype of the concept it defines ("<<<")
+	Expression_SUBTYPE_OF Expression_DefinitionStatus = 1
+)
+
+var Expression_DefinitionStatus_name = map[int32]string{
+	0: "EQUIVALENT_TO",
+	1: "SUBTYPE_OF",
+}
+
+var Expression_DefinitionStatus_value = map[string]int32{
+	"EQUIVALENT_TO": 0,
+	"SUBTYPE_OF":    1,
+}
+
+func (x Expression_DefinitionStatus) String() string {
+	return proto.EnumName(Expression_DefinitionStatus_name, int32(x))
+}
+
+func (Expression_DefinitionStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{11, 0}
+}
+
 type SubsumptionResponse_Result int32
 
 const (
@@ -77,7 +104,38 @@ func (x SearchRequest_Fuzzy) String() string {
 }
 
 func (SearchRequest_Fuzzy) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_f07bb073e3d2b868, []int{16, 0}
+	return fileDescriptor_f07bb073e3d2b868, []int{19, 0}
+}
+
+type SearchRequest_Facet int32
+
+const (
+	// counts by top-level hierarchy, such as clinical finding or procedure
+	SearchRequest_HIERARCHY SearchRequest_Facet = 0
+	// counts by module of the matched description
+	SearchRequest_MODULE SearchRequest_Facet = 1
+	// counts by membership of the reference sets listed in facet_reference_set_ids
+	SearchRequest_REFERENCE_SET SearchRequest_Facet = 2
+)
+
+var SearchRequest_Facet_name = map[int32]string{
+	0: "HIERARCHY",
+	1: "MODULE",
+	2: "REFERENCE_SET",
+}
+
+var SearchRequest_Facet_value = map[string]int32{
+	"HIERARCHY":     0,
+	"MODULE":        1,
+	"REFERENCE_SET": 2,
+}
+
+func (x SearchRequest_Facet) String() string {
+	return proto.EnumName(SearchRequest_Facet_name, int32(x))
+}
+
+func (SearchRequest_Facet) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{19, 1}
 }
 
 // A Concept represents a SNOMED-CT concept.
@@ -1059,9 +1117,13 @@ type ExtendedDescription struct {
 	DirectParentIds      []int64      `protobuf:"varint,6,rep,packed,name=direct_parent_ids,json=directParentIds,proto3" json:"direct_parent_ids,omitempty"`
 	ConceptRefsets       []int64      `protobuf:"varint,7,rep,packed,name=concept_refsets,json=conceptRefsets,proto3" json:"concept_refsets,omitempty"`
 	DescriptionRefsets   []int64      `protobuf:"varint,8,rep,packed,name=description_refsets,json=descriptionRefsets,proto3" json:"description_refsets,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
-	XXX_unrecognized     []byte       `json:"-"`
-	XXX_sizecache        int32        `json:"-"`
+	// language reference sets in which the description is preferred
+	PreferredIn []int64 `protobuf:"varint,9,rep,packed,name=preferred_in,json=preferredIn,proto3" json:"preferred_in,omitempty"`
+	// language reference sets in which the description is acceptable
+	AcceptableIn         []int64  `protobuf:"varint,10,rep,packed,name=acceptable_in,json=acceptableIn,proto3" json:"acceptable_in,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ExtendedDescription) Reset()         { *m = ExtendedDescription{} }
@@ -1138,16 +1200,32 @@ func (m *ExtendedDescription) GetDescriptionRefsets() []int64 {
 	return nil
 }
 
+func (m *ExtendedDescription) GetPreferredIn() []int64 {
+	if m != nil {
+		return m.PreferredIn
+	}
+	return nil
+}
+
+func (m *ExtendedDescription) GetAcceptableIn() []int64 {
+	if m != nil {
+		return m.AcceptableIn
+	}
+	return nil
+}
+
 // Expression represents a compound SNOMED CT expression.
 // There would usually only be a single concept and possibly some refinement
 // See https://confluence.ihtsdotools.org/display/DOCSCG/Compositional+Grammar+-+Specification+and+Guide
 // The ABNF grammar for SNOMED compositional grammar is available here:
 // https://github.com/IHTSDO/SNOMEDCT-Languages/blob/master/SnomedCTCompositionalGrammar/CG%20Syntax/Compositional%20Grammar%20v2%20-%20ABNF%20(Normative).txt
 type Expression struct {
-	Terms                []*Expression_Clause `protobuf:"bytes,1,rep,name=terms,proto3" json:"terms,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	// the focus concepts of the expression, with any refinements held by the first
+	Terms                []*Expression_Clause        `protobuf:"bytes,1,rep,name=terms,proto3" json:"terms,omitempty"`
+	DefinitionStatus     Expression_DefinitionStatus `protobuf:"varint,2,opt,name=definition_status,json=definitionStatus,proto3,enum=snomed.Expression_DefinitionStatus" json:"definition_status,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
 }
 
 func (m *Expression) Reset()         { *m = Expression{} }
@@ -1182,6 +1260,13 @@ func (m *Expression) GetTerms() []*Expression_Clause {
 	return nil
 }
 
+func (m *Expression) GetDefinitionStatus() Expression_DefinitionStatus {
+	if m != nil {
+		return m.DefinitionStatus
+	}
+	return Expression_EQUIVALENT_TO
+}
+
 // Refinement provides an attribute-value pair. Can be nested.
 type Expression_Refinement struct {
 	Attribute *Concept `protobuf:"bytes,1,opt,name=attribute,proto3" json:"attribute,omitempty"`
@@ -1190,6 +1275,7 @@ type Expression_Refinement struct {
 	//	*Expression_Refinement_StringValue
 	//	*Expression_Refinement_IntValue
 	//	*Expression_Refinement_DoubleValue
+	//	*Expression_Refinement_ExpressionValue
 	Value                isExpression_Refinement_Value `protobuf_oneof:"value"`
 	Refinement           *Expression_Refinement        `protobuf:"bytes,6,opt,name=refinement,proto3" json:"refinement,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
@@ -1249,6 +1335,10 @@ type Expression_Refinement_DoubleValue struct {
 	DoubleValue float64 `protobuf:"fixed64,5,opt,name=double_value,json=doubleValue,proto3,oneof"`
 }
 
+type Expression_Refinement_ExpressionValue struct {
+	ExpressionValue *Expression `protobuf:"bytes,7,opt,name=expression_value,json=expressionValue,proto3,oneof"`
+}
+
 func (*Expression_Refinement_ConceptValue) isExpression_Refinement_Value() {}
 
 func (*Expression_Refinement_StringValue) isExpression_Refinement_Value() {}
@@ -1257,6 +1347,8 @@ func (*Expression_Refinement_IntValue) isExpression_Refinement_Value() {}
 
 func (*Expression_Refinement_DoubleValue) isExpression_Refinement_Value() {}
 
+func (*Expression_Refinement_ExpressionValue) isExpression_Refinement_Value() {}
+
 func (m *Expression_Refinement) GetValue() isExpression_Refinement_Value {
 	if m != nil {
 		return m.Value
@@ -1292,6 +1384,13 @@ func (m *Expression_Refinement) GetDoubleValue() float64 {
 	return 0
 }
 
+func (m *Expression_Refinement) GetExpressionValue() *Expression {
+	if x, ok := m.GetValue().(*Expression_Refinement_ExpressionValue); ok {
+		return x.ExpressionValue
+	}
+	return nil
+}
+
 func (m *Expression_Refinement) GetRefinement() *Expression_Refinement {
 	if m != nil {
 		return m.Refinement
@@ -1306,6 +1405,7 @@ func (*Expression_Refinement) XXX_OneofFuncs() (func(msg proto.Message, b *proto
 		(*Expression_Refinement_StringValue)(nil),
 		(*Expression_Refinement_IntValue)(nil),
 		(*Expression_Refinement_DoubleValue)(nil),
+		(*Expression_Refinement_ExpressionValue)(nil),
 	}
 }
 
@@ -1437,11 +1537,13 @@ func (m *Expression_RefinementGroup) GetRefinement() []*Expression_Refinement {
 }
 
 type Expression_Clause struct {
-	Concept              *Concept                      `protobuf:"bytes,1,opt,name=concept,proto3" json:"concept,omitempty"`
-	RefinedGroups        []*Expression_RefinementGroup `protobuf:"bytes,2,rep,name=refined_groups,json=refinedGroups,proto3" json:"refined_groups,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
-	XXX_unrecognized     []byte                        `json:"-"`
-	XXX_sizecache        int32                         `json:"-"`
+	Concept       *Concept                      `protobuf:"bytes,1,opt,name=concept,proto3" json:"concept,omitempty"`
+	RefinedGroups []*Expression_RefinementGroup `protobuf:"bytes,2,rep,name=refined_groups,json=refinedGroups,proto3" json:"refined_groups,omitempty"`
+	// refinements not within an attribute group
+	Refinements          []*Expression_Refinement `protobuf:"bytes,3,rep,name=refinements,proto3" json:"refinements,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
 }
 
 func (m *Expression_Clause) Reset()         { *m = Expression_Clause{} }
@@ -1483,15 +1585,27 @@ func (m *Expression_Clause) GetRefinedGroups() []*Expression_RefinementGroup {
 	return nil
 }
 
+func (m *Expression_Clause) GetRefinements() []*Expression_Refinement {
+	if m != nil {
+		return m.Refinements
+	}
+	return nil
+}
+
 // SubsumptionRequest requests a est of subsumption
 // This is based on on the HL7 FHIR terminology service definition
 // Does concept A subsumes concept B?
 // e.g. A:Disorder of liver, B: viral hepatitis. Result: Subsumes
 // See https://www.hl7.org/fhir/terminology-service.html
 type SubsumptionRequest struct {
-	System               string   `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
-	CodeA                int64    `protobuf:"varint,2,opt,name=code_a,json=codeA,proto3" json:"code_a,omitempty"`
-	CodeB                int64    `protobuf:"varint,3,opt,name=code_b,json=codeB,proto3" json:"code_b,omitempty"`
+	System string `protobuf:"bytes,1,opt,name=system,proto3" json:"system,omitempty"`
+	CodeA  int64  `protobuf:"varint,2,opt,name=code_a,json=codeA,proto3" json:"code_a,omitempty"`
+	CodeB  int64  `protobuf:"varint,3,opt,name=code_b,json=codeB,proto3" json:"code_b,omitempty"`
+	// an expression in the compositional grammar, such as "64572001 : { 363698007 = 21483005 }",
+	// used instead of code_a if specified
+	ExpressionA string `protobuf:"bytes,4,opt,name=expression_a,json=expressionA,proto3" json:"expression_a,omitempty"`
+	// an expression in the compositional grammar, used instead of code_b if specified
+	ExpressionB          string   `protobuf:"bytes,5,opt,name=expression_b,json=expressionB,proto3" json:"expression_b,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1543,6 +1657,20 @@ func (m *SubsumptionRequest) GetCodeB() int64 {
 	return 0
 }
 
+func (m *SubsumptionRequest) GetExpressionA() string {
+	if m != nil {
+		return m.ExpressionA
+	}
+	return ""
+}
+
+func (m *SubsumptionRequest) GetExpressionB() string {
+	if m != nil {
+		return m.ExpressionB
+	}
+	return ""
+}
+
 // SubsumptionResponse gives the response of subsumption testing
 type SubsumptionResponse struct {
 	Result               SubsumptionResponse_Result `protobuf:"varint,1,opt,name=result,proto3,enum=snomed.SubsumptionResponse_Result" json:"result,omitempty"`
@@ -1583,6 +1711,144 @@ func (m *SubsumptionResponse) GetResult() SubsumptionResponse_Result {
 	return SubsumptionResponse_EQUIVALENT
 }
 
+// ConstraintRequest is a request to evaluate an expression constraint, such as "<< 24700007"
+type ConstraintRequest struct {
+	Ecl string `protobuf:"bytes,1,opt,name=ecl,proto3" json:"ecl,omitempty"`
+	// maximum number of concept identifiers to return, or zero for all
+	MaximumHits          int32    `protobuf:"varint,2,opt,name=maximum_hits,json=maximumHits,proto3" json:"maximum_hits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConstraintRequest) Reset()         { *m = ConstraintRequest{} }
+func (m *ConstraintRequest) String() string { return proto.CompactTextString(m) }
+func (*ConstraintRequest) ProtoMessage()    {}
+func (*ConstraintRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{14}
+}
+
+func (m *ConstraintRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConstraintRequest.Unmarshal(m, b)
+}
+func (m *ConstraintRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConstraintRequest.Marshal(b, m, deterministic)
+}
+func (m *ConstraintRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConstraintRequest.Merge(m, src)
+}
+func (m *ConstraintRequest) XXX_Size() int {
+	return xxx_messageInfo_ConstraintRequest.Size(m)
+}
+func (m *ConstraintRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConstraintRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConstraintRequest proto.InternalMessageInfo
+
+func (m *ConstraintRequest) GetEcl() string {
+	if m != nil {
+		return m.Ecl
+	}
+	return ""
+}
+
+func (m *ConstraintRequest) GetMaximumHits() int32 {
+	if m != nil {
+		return m.MaximumHits
+	}
+	return 0
+}
+
+// ConstraintResponse contains the identifiers of the concepts matching an expression constraint
+type ConstraintResponse struct {
+	ConceptIds []int64 `protobuf:"varint,1,rep,packed,name=concept_ids,json=conceptIds,proto3" json:"concept_ids,omitempty"`
+	// total number of matching concepts, which may exceed those returned
+	Total                int64    `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ConstraintResponse) Reset()         { *m = ConstraintResponse{} }
+func (m *ConstraintResponse) String() string { return proto.CompactTextString(m) }
+func (*ConstraintResponse) ProtoMessage()    {}
+func (*ConstraintResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{15}
+}
+
+func (m *ConstraintResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ConstraintResponse.Unmarshal(m, b)
+}
+func (m *ConstraintResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ConstraintResponse.Marshal(b, m, deterministic)
+}
+func (m *ConstraintResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ConstraintResponse.Merge(m, src)
+}
+func (m *ConstraintResponse) XXX_Size() int {
+	return xxx_messageInfo_ConstraintResponse.Size(m)
+}
+func (m *ConstraintResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ConstraintResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ConstraintResponse proto.InternalMessageInfo
+
+func (m *ConstraintResponse) GetConceptIds() []int64 {
+	if m != nil {
+		return m.ConceptIds
+	}
+	return nil
+}
+
+func (m *ConstraintResponse) GetTotal() int64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+// ExpressionRequest is a request to parse an expression written in the SNOMED-CT compositional grammar
+type ExpressionRequest struct {
+	Expression           string   `protobuf:"bytes,1,opt,name=expression,proto3" json:"expression,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExpressionRequest) Reset()         { *m = ExpressionRequest{} }
+func (m *ExpressionRequest) String() string { return proto.CompactTextString(m) }
+func (*ExpressionRequest) ProtoMessage()    {}
+func (*ExpressionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{16}
+}
+
+func (m *ExpressionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExpressionRequest.Unmarshal(m, b)
+}
+func (m *ExpressionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExpressionRequest.Marshal(b, m, deterministic)
+}
+func (m *ExpressionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpressionRequest.Merge(m, src)
+}
+func (m *ExpressionRequest) XXX_Size() int {
+	return xxx_messageInfo_ExpressionRequest.Size(m)
+}
+func (m *ExpressionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpressionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpressionRequest proto.InternalMessageInfo
+
+func (m *ExpressionRequest) GetExpression() string {
+	if m != nil {
+		return m.Expression
+	}
+	return ""
+}
+
 type TranslateRequest struct {
 	ConceptId            int64    `protobuf:"varint,1,opt,name=concept_id,json=conceptId,proto3" json:"concept_id,omitempty"`
 	TargetId             int64    `protobuf:"varint,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
@@ -1595,7 +1861,7 @@ func (m *TranslateRequest) Reset()         { *m = TranslateRequest{} }
 func (m *TranslateRequest) String() string { return proto.CompactTextString(m) }
 func (*TranslateRequest) ProtoMessage()    {}
 func (*TranslateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f07bb073e3d2b868, []int{14}
+	return fileDescriptor_f07bb073e3d2b868, []int{17}
 }
 
 func (m *TranslateRequest) XXX_Unmarshal(b []byte) error {
@@ -1644,7 +1910,7 @@ func (m *TranslateResponse) Reset()         { *m = TranslateResponse{} }
 func (m *TranslateResponse) String() string { return proto.CompactTextString(m) }
 func (*TranslateResponse) ProtoMessage()    {}
 func (*TranslateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f07bb073e3d2b868, []int{15}
+	return fileDescriptor_f07bb073e3d2b868, []int{18}
 }
 
 func (m *TranslateResponse) XXX_Unmarshal(b []byte) error {
@@ -1778,24 +2044,69 @@ func _TranslateResponse_OneofSizer(msg proto.Message) (n int) {
 
 // SearchRequest permits an arbitrary free-text search of the hierarchy.
 type SearchRequest struct {
-	Search               string              `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
-	RecursiveParentIds   []int64             `protobuf:"varint,2,rep,packed,name=recursive_parent_ids,json=recursiveParentIds,proto3" json:"recursive_parent_ids,omitempty"`
-	DirectParentIds      []int64             `protobuf:"varint,3,rep,packed,name=direct_parent_ids,json=directParentIds,proto3" json:"direct_parent_ids,omitempty"`
-	ReferenceSetIds      []int64             `protobuf:"varint,4,rep,packed,name=reference_set_ids,json=referenceSetIds,proto3" json:"reference_set_ids,omitempty"`
-	MaximumHits          int32               `protobuf:"varint,5,opt,name=maximum_hits,json=maximumHits,proto3" json:"maximum_hits,omitempty"`
-	IncludeInactive      bool                `protobuf:"varint,6,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
-	Fuzzy                SearchRequest_Fuzzy `protobuf:"varint,7,opt,name=fuzzy,proto3,enum=snomed.SearchRequest_Fuzzy" json:"fuzzy,omitempty"`
-	AcceptedLanguages    string              `protobuf:"bytes,8,opt,name=accepted_languages,json=acceptedLanguages,proto3" json:"accepted_languages,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
-	XXX_unrecognized     []byte              `json:"-"`
-	XXX_sizecache        int32               `json:"-"`
+	Search             string              `protobuf:"bytes,1,opt,name=search,proto3" json:"search,omitempty"`
+	RecursiveParentIds []int64             `protobuf:"varint,2,rep,packed,name=recursive_parent_ids,json=recursiveParentIds,proto3" json:"recursive_parent_ids,omitempty"`
+	DirectParentIds    []int64             `protobuf:"varint,3,rep,packed,name=direct_parent_ids,json=directParentIds,proto3" json:"direct_parent_ids,omitempty"`
+	ReferenceSetIds    []int64             `protobuf:"varint,4,rep,packed,name=reference_set_ids,json=referenceSetIds,proto3" json:"reference_set_ids,omitempty"`
+	MaximumHits        int32               `protobuf:"varint,5,opt,name=maximum_hits,json=maximumHits,proto3" json:"maximum_hits,omitempty"`
+	IncludeInactive    bool                `protobuf:"varint,6,opt,name=include_inactive,json=includeInactive,proto3" json:"include_inactive,omitempty"`
+	Fuzzy              SearchRequest_Fuzzy `protobuf:"varint,7,opt,name=fuzzy,proto3,enum=snomed.SearchRequest_Fuzzy" json:"fuzzy,omitempty"`
+	AcceptedLanguages  string              `protobuf:"bytes,8,opt,name=accepted_languages,json=acceptedLanguages,proto3" json:"accepted_languages,omitempty"`
+	// number of results to skip, for pagination
+	Offset int32 `protobuf:"varint,9,opt,name=offset,proto3" json:"offset,omitempty"`
+	// opaque cursor from a previous response, used in preference to offset to fetch the next page
+	Cursor string `protobuf:"bytes,10,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// whether to return a highlighted fragment of the matched term for each result
+	Highlight bool `protobuf:"varint,11,opt,name=highlight,proto3" json:"highlight,omitempty"`
+	// facets for which to return counts of matching descriptions
+	Facets []SearchRequest_Facet `protobuf:"varint,12,rep,packed,name=facets,proto3,enum=snomed.SearchRequest_Facet" json:"facets,omitempty"`
+	// reference sets for which to return counts, when the reference set facet is requested
+	FacetReferenceSetIds []int64 `protobuf:"varint,13,rep,packed,name=facet_reference_set_ids,json=facetReferenceSetIds,proto3" json:"facet_reference_set_ids,omitempty"`
+	// whether to include fully specified names, which are excluded by default
+	IncludeFullySpecifiedNames bool `protobuf:"varint,14,opt,name=include_fully_specified_names,json=includeFullySpecifiedNames,proto3" json:"include_fully_specified_names,omitempty"`
+	// whether to exclude synonyms
+	ExcludeSynonyms bool `protobuf:"varint,15,opt,name=exclude_synonyms,json=excludeSynonyms,proto3" json:"exclude_synonyms,omitempty"`
+	// whether to exclude definitions
+	ExcludeDefinitions bool `protobuf:"varint,16,opt,name=exclude_definitions,json=excludeDefinitions,proto3" json:"exclude_definitions,omitempty"`
+	// limit search to descriptions preferred or acceptable in these language reference sets,
+	// derived from accepted_languages if not specified
+	LanguageReferenceSetIds []int64 `protobuf:"varint,17,rep,packed,name=language_reference_set_ids,json=languageReferenceSetIds,proto3" json:"language_reference_set_ids,omitempty"`
+	// whether to return only the best matching description for each concept, so that
+	// maximum_hits and offset refer to distinct concepts rather than descriptions
+	CollapseConcepts bool `protobuf:"varint,18,opt,name=collapse_concepts,json=collapseConcepts,proto3" json:"collapse_concepts,omitempty"`
+	// whether to also match words that sound like those in the search, weighted below other matches;
+	// requires an index built with phonetic encoding
+	Phonetic bool `protobuf:"varint,19,opt,name=phonetic,proto3" json:"phonetic,omitempty"`
+	// weights for ranking results, overriding those configured for the server
+	Ranking *SearchRequest_Ranking `protobuf:"bytes,20,opt,name=ranking,proto3" json:"ranking,omitempty"`
+	// limit search to descriptions that are members of the specified reference sets
+	DescriptionReferenceSetIds []int64 `protobuf:"varint,21,rep,packed,name=description_reference_set_ids,json=descriptionReferenceSetIds,proto3" json:"description_reference_set_ids,omitempty"`
+	// limit search to descriptions from any of the specified modules, such as those of a national edition
+	ModuleIds []int64 `protobuf:"varint,22,rep,packed,name=module_ids,json=moduleIds,proto3" json:"module_ids,omitempty"`
+	// exclude concepts that are members of any of the specified reference sets
+	ExcludeReferenceSetIds []int64 `protobuf:"varint,23,rep,packed,name=exclude_reference_set_ids,json=excludeReferenceSetIds,proto3" json:"exclude_reference_set_ids,omitempty"`
+	// whether to explain how the search was analysed and executed, and how each result was scored
+	Explain bool `protobuf:"varint,24,opt,name=explain,proto3" json:"explain,omitempty"`
+	// limit search to concepts satisfying an expression constraint, such as "<< 71388002 |Procedure|";
+	// simple constraints are applied as filters, while others are evaluated to the matching concepts
+	Ecl string `protobuf:"bytes,25,opt,name=ecl,proto3" json:"ecl,omitempty"`
+	// limit search to descriptions of the specified concepts
+	ConceptIds []int64 `protobuf:"varint,26,rep,packed,name=concept_ids,json=conceptIds,proto3" json:"concept_ids,omitempty"`
+	// whether to suggest a correction for the spelling of the search, which is otherwise suggested
+	// only when there are no results or the results are fuzzy matches
+	Suggest bool `protobuf:"varint,27,opt,name=suggest,proto3" json:"suggest,omitempty"`
+	// whether the concepts of recursive_parent_ids also match, as well as their descendants
+	IncludeRecursiveParents bool     `protobuf:"varint,28,opt,name=include_recursive_parents,json=includeRecursiveParents,proto3" json:"include_recursive_parents,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
 }
 
 func (m *SearchRequest) Reset()         { *m = SearchRequest{} }
 func (m *SearchRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchRequest) ProtoMessage()    {}
 func (*SearchRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f07bb073e3d2b868, []int{16}
+	return fileDescriptor_f07bb073e3d2b868, []int{19}
 }
 
 func (m *SearchRequest) XXX_Unmarshal(b []byte) error {
@@ -1872,104 +2183,787 @@ func (m *SearchRequest) GetAcceptedLanguages() string {
 	return ""
 }
 
-// SearchResponse provides an optimised search response, sufficient for display purposes.
-type SearchResponse struct {
-	Items                []*SearchResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
-	XXX_unrecognized     []byte                 `json:"-"`
-	XXX_sizecache        int32                  `json:"-"`
+func (m *SearchRequest) GetOffset() int32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
 }
 
-func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
-func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
-func (*SearchResponse) ProtoMessage()    {}
-func (*SearchResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f07bb073e3d2b868, []int{17}
+func (m *SearchRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
 }
 
-func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SearchResponse.Unmarshal(m, b)
+func (m *SearchRequest) GetHighlight() bool {
+	if m != nil {
+		return m.Highlight
+	}
+	return false
 }
-func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
+
+func (m *SearchRequest) GetFacets() []SearchRequest_Facet {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
 }
-func (m *SearchResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchResponse.Merge(m, src)
+
+func (m *SearchRequest) GetFacetReferenceSetIds() []int64 {
+	if m != nil {
+		return m.FacetReferenceSetIds
+	}
+	return nil
 }
-func (m *SearchResponse) XXX_Size() int {
-	return xxx_messageInfo_SearchResponse.Size(m)
+
+func (m *SearchRequest) GetIncludeFullySpecifiedNames() bool {
+	if m != nil {
+		return m.IncludeFullySpecifiedNames
+	}
+	return false
 }
-func (m *SearchResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
+
+func (m *SearchRequest) GetExcludeSynonyms() bool {
+	if m != nil {
+		return m.ExcludeSynonyms
+	}
+	return false
 }
 
-var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
+func (m *SearchRequest) GetExcludeDefinitions() bool {
+	if m != nil {
+		return m.ExcludeDefinitions
+	}
+	return false
+}
 
-func (m *SearchResponse) GetItems() []*SearchResponse_Item {
+func (m *SearchRequest) GetLanguageReferenceSetIds() []int64 {
 	if m != nil {
-		return m.Items
+		return m.LanguageReferenceSetIds
 	}
 	return nil
 }
 
-type SearchResponse_Item struct {
-	Term                 string   `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
-	ConceptId            int64    `protobuf:"varint,2,opt,name=concept_id,json=conceptId,proto3" json:"concept_id,omitempty"`
-	PreferredTerm        string   `protobuf:"bytes,3,opt,name=preferred_term,json=preferredTerm,proto3" json:"preferred_term,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+func (m *SearchRequest) GetCollapseConcepts() bool {
+	if m != nil {
+		return m.CollapseConcepts
+	}
+	return false
 }
 
-func (m *SearchResponse_Item) Reset()         { *m = SearchResponse_Item{} }
-func (m *SearchResponse_Item) String() string { return proto.CompactTextString(m) }
-func (*SearchResponse_Item) ProtoMessage()    {}
-func (*SearchResponse_Item) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f07bb073e3d2b868, []int{17, 0}
+func (m *SearchRequest) GetPhonetic() bool {
+	if m != nil {
+		return m.Phonetic
+	}
+	return false
 }
 
-func (m *SearchResponse_Item) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_SearchResponse_Item.Unmarshal(m, b)
-}
-func (m *SearchResponse_Item) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_SearchResponse_Item.Marshal(b, m, deterministic)
+func (m *SearchRequest) GetRanking() *SearchRequest_Ranking {
+	if m != nil {
+		return m.Ranking
+	}
+	return nil
 }
-func (m *SearchResponse_Item) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_SearchResponse_Item.Merge(m, src)
+
+func (m *SearchRequest) GetDescriptionReferenceSetIds() []int64 {
+	if m != nil {
+		return m.DescriptionReferenceSetIds
+	}
+	return nil
 }
-func (m *SearchResponse_Item) XXX_Size() int {
-	return xxx_messageInfo_SearchResponse_Item.Size(m)
+
+func (m *SearchRequest) GetModuleIds() []int64 {
+	if m != nil {
+		return m.ModuleIds
+	}
+	return nil
 }
-func (m *SearchResponse_Item) XXX_DiscardUnknown() {
-	xxx_messageInfo_SearchResponse_Item.DiscardUnknown(m)
+
+func (m *SearchRequest) GetExcludeReferenceSetIds() []int64 {
+	if m != nil {
+		return m.ExcludeReferenceSetIds
+	}
+	return nil
 }
 
-var xxx_messageInfo_SearchResponse_Item proto.InternalMessageInfo
+func (m *SearchRequest) GetExplain() bool {
+	if m != nil {
+		return m.Explain
+	}
+	return false
+}
 
-func (m *SearchResponse_Item) GetTerm() string {
+func (m *SearchRequest) GetEcl() string {
 	if m != nil {
-		return m.Term
+		return m.Ecl
 	}
 	return ""
 }
 
-func (m *SearchResponse_Item) GetConceptId() int64 {
+func (m *SearchRequest) GetConceptIds() []int64 {
 	if m != nil {
-		return m.ConceptId
+		return m.ConceptIds
 	}
-	return 0
+	return nil
 }
 
-func (m *SearchResponse_Item) GetPreferredTerm() string {
+func (m *SearchRequest) GetSuggest() bool {
 	if m != nil {
-		return m.PreferredTerm
+		return m.Suggest
 	}
-	return ""
+	return false
+}
+
+func (m *SearchRequest) GetIncludeRecursiveParents() bool {
+	if m != nil {
+		return m.IncludeRecursiveParents
+	}
+	return false
+}
+
+// Ranking boosts results with particular features above those ranked by relevance alone.
+// A weight of zero uses the weight configured for the server, and a negative weight disables the boost.
+type SearchRequest_Ranking struct {
+	// boost for the preferred term of each concept, over its other synonyms
+	Preferred float32 `protobuf:"fixed32,1,opt,name=preferred,proto3" json:"preferred,omitempty"`
+	// boost for each word of the search that matches a word of the term exactly
+	Exact float32 `protobuf:"fixed32,2,opt,name=exact,proto3" json:"exact,omitempty"`
+	// boost for each word of the search that matches the start of a word of the term,
+	// so that exact and prefix matches rank above fuzzy matches
+	Prefix float32 `protobuf:"fixed32,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// boost for short terms, given in full to terms of a single word and reducing for longer terms
+	ShortTerms float32 `protobuf:"fixed32,4,opt,name=short_terms,json=shortTerms,proto3" json:"short_terms,omitempty"`
+	// boost for concepts that are members of the reference sets in reference_set_ids
+	ReferenceSet float32 `protobuf:"fixed32,5,opt,name=reference_set,json=referenceSet,proto3" json:"reference_set,omitempty"`
+	// reference sets whose members are boosted, such as a subset of commonly used concepts
+	ReferenceSetIds      []int64  `protobuf:"varint,6,rep,packed,name=reference_set_ids,json=referenceSetIds,proto3" json:"reference_set_ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchRequest_Ranking) Reset()         { *m = SearchRequest_Ranking{} }
+func (m *SearchRequest_Ranking) String() string { return proto.CompactTextString(m) }
+func (*SearchRequest_Ranking) ProtoMessage()    {}
+func (*SearchRequest_Ranking) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{19, 0}
+}
+
+func (m *SearchRequest_Ranking) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchRequest_Ranking.Unmarshal(m, b)
+}
+func (m *SearchRequest_Ranking) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchRequest_Ranking.Marshal(b, m, deterministic)
+}
+func (m *SearchRequest_Ranking) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchRequest_Ranking.Merge(m, src)
+}
+func (m *SearchRequest_Ranking) XXX_Size() int {
+	return xxx_messageInfo_SearchRequest_Ranking.Size(m)
+}
+func (m *SearchRequest_Ranking) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchRequest_Ranking.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchRequest_Ranking proto.InternalMessageInfo
+
+func (m *SearchRequest_Ranking) GetPreferred() float32 {
+	if m != nil {
+		return m.Preferred
+	}
+	return 0
+}
+
+func (m *SearchRequest_Ranking) GetExact() float32 {
+	if m != nil {
+		return m.Exact
+	}
+	return 0
+}
+
+func (m *SearchRequest_Ranking) GetPrefix() float32 {
+	if m != nil {
+		return m.Prefix
+	}
+	return 0
+}
+
+func (m *SearchRequest_Ranking) GetShortTerms() float32 {
+	if m != nil {
+		return m.ShortTerms
+	}
+	return 0
+}
+
+func (m *SearchRequest_Ranking) GetReferenceSet() float32 {
+	if m != nil {
+		return m.ReferenceSet
+	}
+	return 0
+}
+
+func (m *SearchRequest_Ranking) GetReferenceSetIds() []int64 {
+	if m != nil {
+		return m.ReferenceSetIds
+	}
+	return nil
+}
+
+// SearchResponse provides an optimised search response, sufficient for display purposes.
+type SearchResponse struct {
+	Items []*SearchResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	// total number of matching descriptions, irrespective of pagination or collapsing by concept
+	TotalHits int64 `protobuf:"varint,2,opt,name=total_hits,json=totalHits,proto3" json:"total_hits,omitempty"`
+	// opaque cursor to fetch the next page of results, empty if there are no more results
+	NextCursor string `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	// counts of matching descriptions for each requested facet
+	Facets []*SearchResponse_FacetResult `protobuf:"bytes,4,rep,name=facets,proto3" json:"facets,omitempty"`
+	// the search string with misspelt words corrected, if any appear to be misspelt
+	Suggestion string `protobuf:"bytes,5,opt,name=suggestion,proto3" json:"suggestion,omitempty"`
+	// how the search was analysed and executed, if requested
+	Explanation          *SearchResponse_Explanation `protobuf:"bytes,6,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
+func (m *SearchResponse) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse) ProtoMessage()    {}
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{20}
+}
+
+func (m *SearchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse.Unmarshal(m, b)
+}
+func (m *SearchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse.Merge(m, src)
+}
+func (m *SearchResponse) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse.Size(m)
+}
+func (m *SearchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse proto.InternalMessageInfo
+
+func (m *SearchResponse) GetItems() []*SearchResponse_Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+func (m *SearchResponse) GetTotalHits() int64 {
+	if m != nil {
+		return m.TotalHits
+	}
+	return 0
+}
+
+func (m *SearchResponse) GetNextCursor() string {
+	if m != nil {
+		return m.NextCursor
+	}
+	return ""
+}
+
+func (m *SearchResponse) GetFacets() []*SearchResponse_FacetResult {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
+func (m *SearchResponse) GetSuggestion() string {
+	if m != nil {
+		return m.Suggestion
+	}
+	return ""
+}
+
+func (m *SearchResponse) GetExplanation() *SearchResponse_Explanation {
+	if m != nil {
+		return m.Explanation
+	}
+	return nil
+}
+
+type SearchResponse_Item struct {
+	Term          string `protobuf:"bytes,1,opt,name=term,proto3" json:"term,omitempty"`
+	ConceptId     int64  `protobuf:"varint,2,opt,name=concept_id,json=conceptId,proto3" json:"concept_id,omitempty"`
+	PreferredTerm string `protobuf:"bytes,3,opt,name=preferred_term,json=preferredTerm,proto3" json:"preferred_term,omitempty"`
+	// relevance score for this result
+	Score float64 `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	// identifier of the matched description
+	DescriptionId int64 `protobuf:"varint,5,opt,name=description_id,json=descriptionId,proto3" json:"description_id,omitempty"`
+	// matched term with the matching fragments highlighted, if requested
+	Highlight string `protobuf:"bytes,6,opt,name=highlight,proto3" json:"highlight,omitempty"`
+	// how the score for this result was calculated, if requested
+	Explanation          string   `protobuf:"bytes,7,opt,name=explanation,proto3" json:"explanation,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchResponse_Item) Reset()         { *m = SearchResponse_Item{} }
+func (m *SearchResponse_Item) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse_Item) ProtoMessage()    {}
+func (*SearchResponse_Item) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{20, 0}
+}
+
+func (m *SearchResponse_Item) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse_Item.Unmarshal(m, b)
+}
+func (m *SearchResponse_Item) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse_Item.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse_Item) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse_Item.Merge(m, src)
+}
+func (m *SearchResponse_Item) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse_Item.Size(m)
+}
+func (m *SearchResponse_Item) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse_Item.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse_Item proto.InternalMessageInfo
+
+func (m *SearchResponse_Item) GetTerm() string {
+	if m != nil {
+		return m.Term
+	}
+	return ""
+}
+
+func (m *SearchResponse_Item) GetConceptId() int64 {
+	if m != nil {
+		return m.ConceptId
+	}
+	return 0
+}
+
+func (m *SearchResponse_Item) GetPreferredTerm() string {
+	if m != nil {
+		return m.PreferredTerm
+	}
+	return ""
+}
+
+func (m *SearchResponse_Item) GetScore() float64 {
+	if m != nil {
+		return m.Score
+	}
+	return 0
+}
+
+func (m *SearchResponse_Item) GetDescriptionId() int64 {
+	if m != nil {
+		return m.DescriptionId
+	}
+	return 0
+}
+
+func (m *SearchResponse_Item) GetHighlight() string {
+	if m != nil {
+		return m.Highlight
+	}
+	return ""
+}
+
+func (m *SearchResponse_Item) GetExplanation() string {
+	if m != nil {
+		return m.Explanation
+	}
+	return ""
+}
+
+type SearchResponse_Explanation struct {
+	// the words of the search, as analysed for matching against the indexed terms
+	Tokens []string `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	// the query generated from the search and the filters of the request
+	Query                string   `protobuf:"bytes,2,opt,name=query,proto3" json:"query,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchResponse_Explanation) Reset()         { *m = SearchResponse_Explanation{} }
+func (m *SearchResponse_Explanation) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse_Explanation) ProtoMessage()    {}
+func (*SearchResponse_Explanation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{20, 1}
+}
+
+func (m *SearchResponse_Explanation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse_Explanation.Unmarshal(m, b)
+}
+func (m *SearchResponse_Explanation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse_Explanation.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse_Explanation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse_Explanation.Merge(m, src)
+}
+func (m *SearchResponse_Explanation) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse_Explanation.Size(m)
+}
+func (m *SearchResponse_Explanation) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse_Explanation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse_Explanation proto.InternalMessageInfo
+
+func (m *SearchResponse_Explanation) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+func (m *SearchResponse_Explanation) GetQuery() string {
+	if m != nil {
+		return m.Query
+	}
+	return ""
+}
+
+type SearchResponse_FacetResult struct {
+	Facet SearchRequest_Facet `protobuf:"varint,1,opt,name=facet,proto3,enum=snomed.SearchRequest_Facet" json:"facet,omitempty"`
+	// counts in descending order
+	Counts               []*SearchResponse_FacetCount `protobuf:"bytes,2,rep,name=counts,proto3" json:"counts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                     `json:"-"`
+	XXX_unrecognized     []byte                       `json:"-"`
+	XXX_sizecache        int32                        `json:"-"`
+}
+
+func (m *SearchResponse_FacetResult) Reset()         { *m = SearchResponse_FacetResult{} }
+func (m *SearchResponse_FacetResult) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse_FacetResult) ProtoMessage()    {}
+func (*SearchResponse_FacetResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{20, 2}
+}
+
+func (m *SearchResponse_FacetResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse_FacetResult.Unmarshal(m, b)
+}
+func (m *SearchResponse_FacetResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse_FacetResult.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse_FacetResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse_FacetResult.Merge(m, src)
+}
+func (m *SearchResponse_FacetResult) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse_FacetResult.Size(m)
+}
+func (m *SearchResponse_FacetResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse_FacetResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse_FacetResult proto.InternalMessageInfo
+
+func (m *SearchResponse_FacetResult) GetFacet() SearchRequest_Facet {
+	if m != nil {
+		return m.Facet
+	}
+	return SearchRequest_HIERARCHY
+}
+
+func (m *SearchResponse_FacetResult) GetCounts() []*SearchResponse_FacetCount {
+	if m != nil {
+		return m.Counts
+	}
+	return nil
+}
+
+type SearchResponse_FacetCount struct {
+	ConceptId            int64    `protobuf:"varint,1,opt,name=concept_id,json=conceptId,proto3" json:"concept_id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Count                int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SearchResponse_FacetCount) Reset()         { *m = SearchResponse_FacetCount{} }
+func (m *SearchResponse_FacetCount) String() string { return proto.CompactTextString(m) }
+func (*SearchResponse_FacetCount) ProtoMessage()    {}
+func (*SearchResponse_FacetCount) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{20, 3}
+}
+
+func (m *SearchResponse_FacetCount) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SearchResponse_FacetCount.Unmarshal(m, b)
+}
+func (m *SearchResponse_FacetCount) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SearchResponse_FacetCount.Marshal(b, m, deterministic)
+}
+func (m *SearchResponse_FacetCount) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchResponse_FacetCount.Merge(m, src)
+}
+func (m *SearchResponse_FacetCount) XXX_Size() int {
+	return xxx_messageInfo_SearchResponse_FacetCount.Size(m)
+}
+func (m *SearchResponse_FacetCount) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchResponse_FacetCount.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchResponse_FacetCount proto.InternalMessageInfo
+
+func (m *SearchResponse_FacetCount) GetConceptId() int64 {
+	if m != nil {
+		return m.ConceptId
+	}
+	return 0
+}
+
+func (m *SearchResponse_FacetCount) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SearchResponse_FacetCount) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// StatisticsRequest is a request for summary statistics for the datastore
+type StatisticsRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatisticsRequest) Reset()         { *m = StatisticsRequest{} }
+func (m *StatisticsRequest) String() string { return proto.CompactTextString(m) }
+func (*StatisticsRequest) ProtoMessage()    {}
+func (*StatisticsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{21}
+}
+
+func (m *StatisticsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatisticsRequest.Unmarshal(m, b)
+}
+func (m *StatisticsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatisticsRequest.Marshal(b, m, deterministic)
+}
+func (m *StatisticsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatisticsRequest.Merge(m, src)
+}
+func (m *StatisticsRequest) XXX_Size() int {
+	return xxx_messageInfo_StatisticsRequest.Size(m)
+}
+func (m *StatisticsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatisticsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatisticsRequest proto.InternalMessageInfo
+
+// Statistics provides summary statistics for the datastore
+type Statistics struct {
+	Concepts                int64 `protobuf:"varint,1,opt,name=concepts,proto3" json:"concepts,omitempty"`
+	ActiveConcepts          int64 `protobuf:"varint,2,opt,name=active_concepts,json=activeConcepts,proto3" json:"active_concepts,omitempty"`
+	Descriptions            int64 `protobuf:"varint,3,opt,name=descriptions,proto3" json:"descriptions,omitempty"`
+	ActiveDescriptions      int64 `protobuf:"varint,4,opt,name=active_descriptions,json=activeDescriptions,proto3" json:"active_descriptions,omitempty"`
+	Relationships           int64 `protobuf:"varint,5,opt,name=relationships,proto3" json:"relationships,omitempty"`
+	ActiveRelationships     int64 `protobuf:"varint,6,opt,name=active_relationships,json=activeRelationships,proto3" json:"active_relationships,omitempty"`
+	ReferenceSetItems       int64 `protobuf:"varint,7,opt,name=reference_set_items,json=referenceSetItems,proto3" json:"reference_set_items,omitempty"`
+	ActiveReferenceSetItems int64 `protobuf:"varint,8,opt,name=active_reference_set_items,json=activeReferenceSetItems,proto3" json:"active_reference_set_items,omitempty"`
+	// number of components in each module
+	Modules []*Statistics_Count `protobuf:"bytes,9,rep,name=modules,proto3" json:"modules,omitempty"`
+	// number of active concepts within each top-level hierarchy
+	Hierarchies []*Statistics_Count `protobuf:"bytes,10,rep,name=hierarchies,proto3" json:"hierarchies,omitempty"`
+	// number of items in each language reference set
+	LanguageReferenceSets []*Statistics_Count `protobuf:"bytes,11,rep,name=language_reference_sets,json=languageReferenceSets,proto3" json:"language_reference_sets,omitempty"`
+	// number of items in each reference set
+	ReferenceSets        []*Statistics_Count `protobuf:"bytes,12,rep,name=reference_sets,json=referenceSets,proto3" json:"reference_sets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *Statistics) Reset()         { *m = Statistics{} }
+func (m *Statistics) String() string { return proto.CompactTextString(m) }
+func (*Statistics) ProtoMessage()    {}
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{22}
+}
+
+func (m *Statistics) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Statistics.Unmarshal(m, b)
+}
+func (m *Statistics) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Statistics.Marshal(b, m, deterministic)
+}
+func (m *Statistics) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Statistics.Merge(m, src)
+}
+func (m *Statistics) XXX_Size() int {
+	return xxx_messageInfo_Statistics.Size(m)
+}
+func (m *Statistics) XXX_DiscardUnknown() {
+	xxx_messageInfo_Statistics.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Statistics proto.InternalMessageInfo
+
+func (m *Statistics) GetConcepts() int64 {
+	if m != nil {
+		return m.Concepts
+	}
+	return 0
+}
+
+func (m *Statistics) GetActiveConcepts() int64 {
+	if m != nil {
+		return m.ActiveConcepts
+	}
+	return 0
+}
+
+func (m *Statistics) GetDescriptions() int64 {
+	if m != nil {
+		return m.Descriptions
+	}
+	return 0
+}
+
+func (m *Statistics) GetActiveDescriptions() int64 {
+	if m != nil {
+		return m.ActiveDescriptions
+	}
+	return 0
+}
+
+func (m *Statistics) GetRelationships() int64 {
+	if m != nil {
+		return m.Relationships
+	}
+	return 0
+}
+
+func (m *Statistics) GetActiveRelationships() int64 {
+	if m != nil {
+		return m.ActiveRelationships
+	}
+	return 0
+}
+
+func (m *Statistics) GetReferenceSetItems() int64 {
+	if m != nil {
+		return m.ReferenceSetItems
+	}
+	return 0
+}
+
+func (m *Statistics) GetActiveReferenceSetItems() int64 {
+	if m != nil {
+		return m.ActiveReferenceSetItems
+	}
+	return 0
+}
+
+func (m *Statistics) GetModules() []*Statistics_Count {
+	if m != nil {
+		return m.Modules
+	}
+	return nil
+}
+
+func (m *Statistics) GetHierarchies() []*Statistics_Count {
+	if m != nil {
+		return m.Hierarchies
+	}
+	return nil
+}
+
+func (m *Statistics) GetLanguageReferenceSets() []*Statistics_Count {
+	if m != nil {
+		return m.LanguageReferenceSets
+	}
+	return nil
+}
+
+func (m *Statistics) GetReferenceSets() []*Statistics_Count {
+	if m != nil {
+		return m.ReferenceSets
+	}
+	return nil
+}
+
+// Count is a number of components associated with a named concept, such as a module or reference set
+type Statistics_Count struct {
+	ConceptId            int64    `protobuf:"varint,1,opt,name=concept_id,json=conceptId,proto3" json:"concept_id,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Count                int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Statistics_Count) Reset()         { *m = Statistics_Count{} }
+func (m *Statistics_Count) String() string { return proto.CompactTextString(m) }
+func (*Statistics_Count) ProtoMessage()    {}
+func (*Statistics_Count) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f07bb073e3d2b868, []int{22, 0}
+}
+
+func (m *Statistics_Count) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Statistics_Count.Unmarshal(m, b)
+}
+func (m *Statistics_Count) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Statistics_Count.Marshal(b, m, deterministic)
+}
+func (m *Statistics_Count) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Statistics_Count.Merge(m, src)
+}
+func (m *Statistics_Count) XXX_Size() int {
+	return xxx_messageInfo_Statistics_Count.Size(m)
+}
+func (m *Statistics_Count) XXX_DiscardUnknown() {
+	xxx_messageInfo_Statistics_Count.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Statistics_Count proto.InternalMessageInfo
+
+func (m *Statistics_Count) GetConceptId() int64 {
+	if m != nil {
+		return m.ConceptId
+	}
+	return 0
+}
+
+func (m *Statistics_Count) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Statistics_Count) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
 }
 
 func init() {
+	proto.RegisterEnum("snomed.Expression_DefinitionStatus", Expression_DefinitionStatus_name, Expression_DefinitionStatus_value)
 	proto.RegisterEnum("snomed.SubsumptionResponse_Result", SubsumptionResponse_Result_name, SubsumptionResponse_Result_value)
 	proto.RegisterEnum("snomed.SearchRequest_Fuzzy", SearchRequest_Fuzzy_name, SearchRequest_Fuzzy_value)
+	proto.RegisterEnum("snomed.SearchRequest_Facet", SearchRequest_Facet_name, SearchRequest_Facet_value)
 	proto.RegisterType((*Concept)(nil), "snomed.Concept")
 	proto.RegisterType((*Description)(nil), "snomed.Description")
 	proto.RegisterType((*Relationship)(nil), "snomed.Relationship")
@@ -1987,121 +2981,194 @@ func init() {
 	proto.RegisterType((*Expression_Clause)(nil), "snomed.Expression.Clause")
 	proto.RegisterType((*SubsumptionRequest)(nil), "snomed.SubsumptionRequest")
 	proto.RegisterType((*SubsumptionResponse)(nil), "snomed.SubsumptionResponse")
+	proto.RegisterType((*ConstraintRequest)(nil), "snomed.ConstraintRequest")
+	proto.RegisterType((*ConstraintResponse)(nil), "snomed.ConstraintResponse")
+	proto.RegisterType((*ExpressionRequest)(nil), "snomed.ExpressionRequest")
 	proto.RegisterType((*TranslateRequest)(nil), "snomed.TranslateRequest")
 	proto.RegisterType((*TranslateResponse)(nil), "snomed.TranslateResponse")
 	proto.RegisterType((*SearchRequest)(nil), "snomed.SearchRequest")
+	proto.RegisterType((*SearchRequest_Ranking)(nil), "snomed.SearchRequest.Ranking")
 	proto.RegisterType((*SearchResponse)(nil), "snomed.SearchResponse")
 	proto.RegisterType((*SearchResponse_Item)(nil), "snomed.SearchResponse.Item")
+	proto.RegisterType((*SearchResponse_Explanation)(nil), "snomed.SearchResponse.Explanation")
+	proto.RegisterType((*SearchResponse_FacetResult)(nil), "snomed.SearchResponse.FacetResult")
+	proto.RegisterType((*SearchResponse_FacetCount)(nil), "snomed.SearchResponse.FacetCount")
+	proto.RegisterType((*StatisticsRequest)(nil), "snomed.StatisticsRequest")
+	proto.RegisterType((*Statistics)(nil), "snomed.Statistics")
+	proto.RegisterType((*Statistics_Count)(nil), "snomed.Statistics.Count")
 }
 
 func init() { proto.RegisterFile("snomed.proto", fileDescriptor_f07bb073e3d2b868) }
 
 var fileDescriptor_f07bb073e3d2b868 = []byte{
-	// 1684 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x58, 0xcd, 0x6f, 0x1b, 0xc7,
-	0x15, 0xd7, 0xf2, 0x4b, 0xe4, 0xa3, 0x44, 0x52, 0x63, 0xd9, 0x61, 0x64, 0xbb, 0x51, 0x37, 0x0d,
-	0xa2, 0x24, 0x08, 0xed, 0xa8, 0xa9, 0x51, 0xa4, 0x68, 0x01, 0x52, 0x96, 0xab, 0x6d, 0x15, 0x5b,
-	0x5d, 0xca, 0x29, 0xec, 0xcb, 0x76, 0xb5, 0x33, 0xa4, 0x07, 0xd8, 0xaf, 0xce, 0xcc, 0x1a, 0x52,
-	0x0e, 0xfd, 0x2b, 0x7a, 0x2c, 0x7a, 0x6a, 0x8f, 0x3d, 0x15, 0xe8, 0xbd, 0xe8, 0xa9, 0xff, 0x50,
-	0x81, 0x9e, 0x5a, 0xcc, 0xd7, 0xee, 0x92, 0x96, 0x93, 0x18, 0x28, 0x50, 0xdf, 0x34, 0xbf, 0xf7,
-	0x7b, 0x8f, 0x6f, 0x7e, 0xf3, 0xe6, 0xcd, 0x5b, 0xc1, 0x16, 0x4f, 0xb3, 0x84, 0xe0, 0x49, 0xce,
-	0x32, 0x91, 0xa1, 0x8e, 0x5e, 0xed, 0xbd, 0xb7, 0xcc, 0xb2, 0x65, 0x4c, 0xee, 0x29, 0xf4, 0xa2,
-	0x58, 0xdc, 0x13, 0x34, 0x21, 0x5c, 0x84, 0x49, 0xae, 0x89, 0xee, 0x3f, 0x1c, 0xd8, 0x3c, 0xca,
-	0xd2, 0x88, 0xe4, 0x02, 0x0d, 0xa0, 0x41, 0xf1, 0xd8, 0xd9, 0x77, 0x0e, 0x9a, 0x7e, 0x83, 0x62,
-	0x34, 0x85, 0x01, 0x59, 0x2c, 0x48, 0x24, 0xe8, 0x4b, 0x12, 0x48, 0xc7, 0x71, 0x63, 0xdf, 0x39,
-	0xe8, 0x1f, 0xee, 0x4d, 0x74, 0xd4, 0x89, 0x8d, 0x3a, 0x39, 0xb7, 0x51, 0xfd, 0xed, 0xd2, 0x43,
-	0x62, 0xe8, 0x16, 0x74, 0x42, 0xb5, 0x1a, 0x37, 0xf7, 0x9d, 0x83, 0xae, 0x6f, 0x56, 0xe8, 0x36,
-	0xf4, 0x92, 0x0c, 0x17, 0x31, 0x09, 0x28, 0x1e, 0xb7, 0xd4, 0x2f, 0x76, 0x35, 0xe0, 0x61, 0x74,
-	0x1f, 0x76, 0x31, 0x59, 0xd0, 0x94, 0x0a, 0x9a, 0xa5, 0x01, 0x17, 0xa1, 0x28, 0xb8, 0xe4, 0xb5,
-	0x15, 0x0f, 0x55, 0xb6, 0xb9, 0x32, 0x79, 0xd8, 0xfd, 0x6b, 0x03, 0xfa, 0x0f, 0x09, 0x8f, 0x18,
-	0xcd, 0x25, 0xfe, 0xd6, 0xec, 0xe4, 0x2e, 0x40, 0xa4, 0xc5, 0xad, 0xf2, 0xef, 0x19, 0xc4, 0xc3,
-	0xe8, 0x7d, 0xd8, 0x8e, 0xc3, 0x74, 0x59, 0x84, 0x4b, 0x12, 0x44, 0x19, 0x26, 0xe3, 0xce, 0xbe,
-	0x73, 0xd0, 0xf3, 0xb7, 0x2c, 0x78, 0x94, 0x61, 0x82, 0xde, 0x81, 0x4d, 0x71, 0x95, 0xab, 0xf0,
-	0x9b, 0x2a, 0x40, 0x47, 0x2e, 0x3d, 0x8c, 0x10, 0xb4, 0x04, 0x61, 0xc9, 0xb8, 0xab, 0x9c, 0xd4,
-	0xdf, 0xe8, 0x13, 0xd8, 0x89, 0x42, 0x4e, 0x02, 0x4e, 0x97, 0x29, 0x5d, 0xd0, 0x28, 0x4c, 0x23,
-	0x32, 0xee, 0x29, 0xb7, 0x91, 0x34, 0xcc, 0x6b, 0xb8, 0xfb, 0xef, 0x06, 0x6c, 0xf9, 0x24, 0x0e,
-	0xa5, 0x64, 0xfc, 0x05, 0xcd, 0xdf, 0x1a, 0xd9, 0x6e, 0x43, 0x8f, 0x67, 0x05, 0x8b, 0x48, 0xa5,
-	0x5a, 0x57, 0x03, 0x1e, 0x46, 0x1f, 0xc0, 0x00, 0x13, 0x2e, 0x68, 0xaa, 0xf2, 0x96, 0x8c, 0x8e,
-	0x62, 0x6c, 0xd7, 0x50, 0x0f, 0xa3, 0x4f, 0x01, 0xb1, 0xda, 0xde, 0x82, 0x25, 0xcb, 0x8a, 0xdc,
-	0x28, 0xb8, 0x53, 0xb7, 0xfc, 0x5c, 0x1a, 0xea, 0x2a, 0x77, 0x57, 0x54, 0xfe, 0x1c, 0x6e, 0x45,
-	0x2f, 0x42, 0x16, 0x46, 0x82, 0x30, 0xca, 0x05, 0x8d, 0x02, 0xcb, 0xd3, 0xb2, 0xee, 0xae, 0x5a,
-	0xcf, 0xb5, 0xd7, 0x7b, 0xd0, 0x4f, 0x32, 0x4c, 0x17, 0x94, 0x30, 0x49, 0x05, 0x45, 0x05, 0x0b,
-	0x79, 0xd8, 0xfd, 0x7b, 0x0b, 0x46, 0x3e, 0x59, 0x10, 0x46, 0xd2, 0x88, 0xcc, 0x89, 0xf0, 0x04,
-	0x49, 0x6a, 0xfa, 0xf7, 0xfe, 0xdf, 0xfa, 0x33, 0xb2, 0xe0, 0xa4, 0x56, 0xb5, 0x5d, 0x0d, 0x78,
-	0x18, 0x3d, 0x80, 0x77, 0x98, 0x4d, 0x1c, 0x07, 0x51, 0x96, 0xe4, 0x59, 0x4a, 0x52, 0x51, 0x1d,
-	0xc4, 0xcd, 0xca, 0x7c, 0x64, 0xad, 0x1e, 0x46, 0x73, 0xd8, 0x31, 0x41, 0xb1, 0xb9, 0xa9, 0x19,
-	0x53, 0xe7, 0xd1, 0x3f, 0xfc, 0xc1, 0xc4, 0x34, 0x2f, 0x9f, 0x2c, 0xe6, 0x44, 0x3c, 0x2c, 0xed,
-	0x75, 0x85, 0x4e, 0x36, 0xfc, 0x91, 0x0e, 0x50, 0xd9, 0xd1, 0xe7, 0xd0, 0xe1, 0x34, 0xc9, 0x63,
-	0xa2, 0x4e, 0x4d, 0x2a, 0x63, 0x22, 0xcd, 0x15, 0xba, 0xe6, 0x6f, 0xb8, 0xe8, 0x0b, 0xe8, 0xda,
-	0x2b, 0xa6, 0x4e, 0xb1, 0x7f, 0x78, 0xc7, 0xfa, 0x9d, 0x1a, 0x7c, 0xcd, 0xb3, 0xe4, 0xa3, 0x9f,
-	0x01, 0xe8, 0x28, 0x41, 0x12, 0xe6, 0xea, 0x60, 0xfb, 0x87, 0x77, 0x57, 0x7f, 0xf5, 0xcb, 0x30,
-	0x5f, 0x73, 0xef, 0x71, 0x6b, 0x40, 0x53, 0xe8, 0x4b, 0xcd, 0x62, 0x72, 0xa9, 0x02, 0xf4, 0x55,
-	0x80, 0xef, 0xd9, 0x00, 0x47, 0xda, 0xf4, 0x6a, 0x04, 0x88, 0x4a, 0xcb, 0xac, 0x03, 0xad, 0x8b,
-	0x0c, 0x5f, 0xb9, 0x7f, 0x71, 0xe0, 0xce, 0x37, 0x29, 0x86, 0x7e, 0x0c, 0xe3, 0x50, 0x08, 0x46,
-	0x2f, 0x0a, 0x41, 0x4a, 0xd5, 0xcd, 0xa5, 0xd1, 0xb7, 0xfc, 0x56, 0x69, 0xaf, 0xb5, 0x4f, 0x0f,
-	0xa3, 0x8f, 0x61, 0xa7, 0xf2, 0xb4, 0x05, 0xdf, 0x50, 0x2e, 0xc3, 0xd2, 0x60, 0x6a, 0xfd, 0x43,
-	0xa8, 0xa0, 0x20, 0x63, 0x98, 0x30, 0x55, 0x6b, 0xdb, 0xfe, 0xa0, 0x84, 0x9f, 0x48, 0xd4, 0xdd,
-	0x05, 0xf4, 0xea, 0xb1, 0xb8, 0x53, 0xd8, 0xbd, 0x4e, 0x74, 0xf4, 0x11, 0x8c, 0xc2, 0x48, 0x36,
-	0xca, 0xf0, 0x82, 0xc6, 0x54, 0x5c, 0x55, 0x49, 0x0f, 0x57, 0x70, 0x0f, 0xbb, 0x0f, 0xe0, 0xe6,
-	0xb5, 0xca, 0xcb, 0xfe, 0x9b, 0x84, 0x79, 0x20, 0x42, 0xb6, 0x24, 0xc2, 0x5c, 0xac, 0x5e, 0x12,
-	0xe6, 0xe7, 0x0a, 0x70, 0xff, 0xe5, 0xc0, 0xad, 0xeb, 0x15, 0x57, 0xf7, 0x23, 0xb4, 0x5d, 0xc3,
-	0x31, 0xf7, 0x23, 0x34, 0xcd, 0xe2, 0xfb, 0xb0, 0x25, 0x8d, 0x39, 0xa3, 0x19, 0xa3, 0xe2, 0xca,
-	0x08, 0xd3, 0x4f, 0xc2, 0xfc, 0xcc, 0x40, 0xe8, 0x5d, 0x90, 0xf4, 0x80, 0x15, 0xb1, 0xbe, 0x79,
-	0x3d, 0x7f, 0x33, 0x09, 0x73, 0xbf, 0x88, 0x89, 0x4d, 0x2a, 0xc4, 0x2f, 0x69, 0x44, 0xd4, 0xdd,
-	0xd3, 0x49, 0x4d, 0x15, 0xb0, 0x96, 0x73, 0x7b, 0x2d, 0x67, 0xb4, 0x2f, 0xeb, 0x87, 0xd9, 0x06,
-	0x66, 0xae, 0x5c, 0x1d, 0xb2, 0xd9, 0x45, 0xa1, 0x20, 0xcb, 0x8c, 0x5d, 0x99, 0x9e, 0x27, 0xb3,
-	0x3b, 0x32, 0x90, 0xfb, 0xcf, 0x06, 0x0c, 0x8f, 0x2f, 0x05, 0x49, 0xb1, 0xbc, 0xa3, 0xfa, 0xf5,
-	0xff, 0x08, 0x36, 0xcd, 0xcb, 0xa4, 0xf6, 0xdb, 0x3f, 0x1c, 0x56, 0x45, 0xa9, 0x60, 0xdf, 0xda,
-	0xd1, 0x17, 0xb0, 0x5d, 0xef, 0xa0, 0x7c, 0xdc, 0xd8, 0x6f, 0x1e, 0xf4, 0x0f, 0x77, 0xab, 0x6b,
-	0x5c, 0x19, 0xfd, 0x55, 0x2a, 0x3a, 0x81, 0x9b, 0xb9, 0x6a, 0x10, 0x8c, 0xe0, 0x7a, 0x4d, 0x2a,
-	0x95, 0xfa, 0x87, 0x37, 0x6c, 0x8c, 0x5a, 0x3d, 0xfa, 0xbb, 0xa5, 0x47, 0xfd, 0x91, 0xbf, 0x0f,
-	0xbb, 0x8c, 0x44, 0x05, 0xe3, 0xb2, 0x3b, 0xe6, 0x21, 0xd3, 0x4d, 0x88, 0x8f, 0x5b, 0xfb, 0x4d,
-	0x39, 0x26, 0x94, 0xb6, 0x33, 0x65, 0xf2, 0x30, 0x97, 0x55, 0x8d, 0x29, 0x23, 0x91, 0xa8, 0xd3,
-	0xdb, 0x8a, 0x3e, 0xd4, 0x86, 0x8a, 0xfb, 0x21, 0x0c, 0xed, 0xd3, 0xad, 0xbb, 0x0e, 0x1f, 0x77,
-	0x14, 0x73, 0x60, 0x60, 0x5f, 0xa3, 0xee, 0x7f, 0x1a, 0x70, 0xc3, 0x6a, 0x59, 0x4f, 0xef, 0x47,
-	0xd0, 0xaf, 0x6f, 0xcf, 0x79, 0xfd, 0xf6, 0xea, 0xbc, 0xfa, 0x31, 0x34, 0xbf, 0xe5, 0x18, 0x5e,
-	0x2b, 0x65, 0xeb, 0x7f, 0x25, 0x65, 0xfb, 0xcd, 0xa4, 0xec, 0x7c, 0x67, 0x29, 0x37, 0xaf, 0x93,
-	0x12, 0xdd, 0x83, 0x1b, 0xf5, 0x2e, 0x65, 0xc9, 0x5d, 0x9d, 0x45, 0xcd, 0x64, 0x1c, 0x7e, 0xd1,
-	0xea, 0x36, 0x46, 0x4d, 0xf7, 0x6f, 0x2d, 0x80, 0xe3, 0xcb, 0x9c, 0x11, 0xce, 0xe5, 0x66, 0xee,
-	0x41, 0x5b, 0xce, 0x42, 0x7c, 0xec, 0xa8, 0xaa, 0x7c, 0xd7, 0xca, 0x50, 0x51, 0x26, 0x47, 0x71,
-	0x58, 0x70, 0xe2, 0x6b, 0xde, 0xde, 0x9f, 0x1a, 0x00, 0xbe, 0x1c, 0x2a, 0x49, 0x42, 0x52, 0x81,
-	0x3e, 0x85, 0x5e, 0xd9, 0xb8, 0x5e, 0x77, 0x15, 0x2a, 0x06, 0x7a, 0x00, 0xdb, 0x76, 0x77, 0x2f,
-	0xc3, 0xb8, 0xb0, 0x6f, 0xf4, 0xba, 0xcb, 0xc9, 0x86, 0xbf, 0x65, 0x78, 0x5f, 0x49, 0x1a, 0x7a,
-	0x1f, 0xb6, 0xb8, 0x60, 0x34, 0x5d, 0x1a, 0x37, 0xd5, 0x25, 0x4e, 0x36, 0xfc, 0xbe, 0x46, 0x35,
-	0xe9, 0x2e, 0xf4, 0x68, 0x6a, 0x03, 0xab, 0x67, 0x5a, 0x3e, 0x46, 0x34, 0xad, 0x62, 0xe0, 0xac,
-	0xb8, 0x88, 0x89, 0x61, 0xc8, 0x6e, 0xe1, 0xc8, 0x18, 0x1a, 0xd5, 0xa4, 0x9f, 0x02, 0xb0, 0x72,
-	0x77, 0xaa, 0x61, 0xd4, 0x5e, 0xac, 0x9a, 0x28, 0x95, 0x04, 0x7e, 0xcd, 0x61, 0xb6, 0x09, 0x6d,
-	0x15, 0x7c, 0xef, 0x0c, 0x86, 0x15, 0x45, 0x37, 0xc2, 0xd5, 0xd0, 0x5a, 0xef, 0xef, 0x1e, 0x7a,
-	0xef, 0x77, 0xd0, 0xd1, 0x27, 0xf1, 0x26, 0xcd, 0xc7, 0x83, 0x81, 0x0e, 0x81, 0x75, 0x77, 0xb6,
-	0xdd, 0xc7, 0xfd, 0xc6, 0xdf, 0x55, 0xf9, 0xca, 0x5e, 0xa4, 0x3c, 0xd5, 0x8a, 0xbb, 0xcf, 0x01,
-	0xcd, 0x8b, 0x0b, 0x5e, 0x24, 0xa6, 0xa8, 0x7e, 0x5b, 0x10, 0x2e, 0xe4, 0xc8, 0xc4, 0xaf, 0xb8,
-	0x20, 0x89, 0x79, 0x30, 0xcc, 0x0a, 0xdd, 0x84, 0x8e, 0x1c, 0xd2, 0x83, 0xd0, 0xf4, 0xfb, 0xb6,
-	0x5c, 0x4d, 0x4b, 0xf8, 0x42, 0x9d, 0xa0, 0x81, 0x67, 0xee, 0x1f, 0x1c, 0xb8, 0xb1, 0x12, 0x9c,
-	0xe7, 0x59, 0xca, 0xe5, 0xec, 0xd1, 0x61, 0x84, 0x17, 0xb1, 0xde, 0xe8, 0xa0, 0x4a, 0xfb, 0x1a,
-	0xf2, 0xc4, 0x57, 0x4c, 0xdf, 0x78, 0xb8, 0x1e, 0x74, 0x34, 0x82, 0x06, 0x00, 0xc7, 0xbf, 0x7a,
-	0xea, 0x7d, 0x35, 0x3d, 0x3d, 0x7e, 0x7c, 0x3e, 0xda, 0x40, 0x5b, 0xd0, 0x9d, 0x3f, 0x9d, 0xcd,
-	0x9f, 0x7e, 0x79, 0x3c, 0x1f, 0x39, 0x68, 0x08, 0x7d, 0xb3, 0x7a, 0x18, 0xcc, 0x9e, 0x8d, 0x1a,
-	0x68, 0x04, 0x5b, 0x8f, 0x9f, 0x9c, 0x07, 0x16, 0x1c, 0x35, 0xdd, 0xc7, 0x30, 0x3a, 0x67, 0x61,
-	0xca, 0xe3, 0x50, 0x10, 0xbb, 0xf1, 0xd5, 0xaf, 0x15, 0x67, 0xfd, 0x6b, 0xe5, 0x36, 0xf4, 0xf4,
-	0xa3, 0x54, 0xcd, 0x02, 0x5d, 0x0d, 0x78, 0xd8, 0xfd, 0xbd, 0x03, 0x3b, 0xb5, 0x80, 0x66, 0xb3,
-	0x9f, 0x7c, 0xdb, 0xb1, 0x9e, 0x6c, 0xd4, 0xdb, 0x19, 0x2a, 0x27, 0xc7, 0x40, 0x0d, 0x9f, 0xf2,
-	0x0c, 0xf4, 0x6d, 0x1a, 0xd7, 0x26, 0xc4, 0x95, 0x99, 0xd9, 0x4c, 0x85, 0x2b, 0xd8, 0xac, 0x6b,
-	0x35, 0x76, 0xff, 0xd8, 0x84, 0xed, 0x39, 0x09, 0x59, 0xf4, 0xa2, 0x7e, 0xba, 0x0a, 0x28, 0x4f,
-	0x57, 0xad, 0x5e, 0xdb, 0x02, 0x1b, 0x6f, 0xd6, 0x02, 0x9b, 0xd7, 0xb7, 0xc0, 0x8f, 0xd5, 0xf0,
-	0x5b, 0xdf, 0x5b, 0xf9, 0x50, 0x0d, 0x57, 0xd2, 0xc7, 0x5c, 0xbf, 0xdf, 0x97, 0x34, 0x29, 0x92,
-	0xe0, 0x05, 0x15, 0x5c, 0x5d, 0xea, 0xb6, 0x7c, 0xbf, 0x15, 0x76, 0x42, 0x05, 0x97, 0xb3, 0x11,
-	0x4d, 0xa3, 0xb8, 0xc0, 0x24, 0xa0, 0xa9, 0x99, 0xef, 0x3b, 0x6a, 0xbe, 0x1f, 0x1a, 0xdc, 0x33,
-	0x30, 0xfa, 0x0c, 0xda, 0x8b, 0xe2, 0xeb, 0xaf, 0xf5, 0x18, 0x30, 0x38, 0xbc, 0x5d, 0x96, 0x5b,
-	0x5d, 0x95, 0xc9, 0x23, 0x49, 0xf1, 0x35, 0x53, 0x7e, 0x3a, 0xe9, 0x09, 0x8b, 0xe0, 0xc0, 0xce,
-	0xbd, 0xdc, 0x7c, 0x66, 0xee, 0x58, 0x8b, 0x9d, 0xd9, 0xb8, 0xfb, 0x13, 0x68, 0x2b, 0x77, 0x84,
-	0x60, 0xf0, 0x68, 0x7a, 0x7a, 0x3a, 0x9b, 0x1e, 0xfd, 0x32, 0x78, 0xf4, 0xf4, 0xf9, 0xf3, 0x67,
-	0xa3, 0x0d, 0x59, 0x79, 0xd3, 0xd3, 0x5f, 0x4f, 0x9f, 0xcd, 0x0d, 0xe2, 0xc8, 0x52, 0x7d, 0xfc,
-	0xc4, 0xac, 0x1a, 0xee, 0x9f, 0x1d, 0x18, 0xd8, 0x54, 0x4c, 0xd1, 0x7c, 0x06, 0x6d, 0x79, 0xf2,
-	0xb6, 0x7f, 0xbf, 0x92, 0xb1, 0xb9, 0x1b, 0xf2, 0xa4, 0x7d, 0xcd, 0xdc, 0xfb, 0x0d, 0xb4, 0xd4,
-	0x07, 0x94, 0xfd, 0x24, 0x76, 0x6a, 0x9f, 0xc4, 0xab, 0x55, 0xdd, 0x58, 0xaf, 0xea, 0x0f, 0x60,
-	0x50, 0x3d, 0xa2, 0xca, 0x59, 0x8f, 0x6b, 0xdb, 0x25, 0x7a, 0x4e, 0x58, 0x32, 0xbb, 0x0f, 0x77,
-	0xa2, 0x2c, 0x99, 0x90, 0x18, 0x33, 0x7a, 0x39, 0x91, 0x3c, 0x9a, 0x66, 0x71, 0xb6, 0xbc, 0x9a,
-	0x24, 0x19, 0x26, 0xf1, 0xac, 0x73, 0x26, 0x3f, 0xc5, 0xf8, 0x99, 0xf3, 0xdc, 0xfc, 0xeb, 0xe5,
-	0xa2, 0xa3, 0x3e, 0xce, 0x7e, 0xf8, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xaa, 0xef, 0x41, 0x5b,
-	0x99, 0x11, 0x00, 0x00,
+	// 2698 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x59, 0xdd, 0x6e, 0x1b, 0xc7,
+	0x15, 0xd6, 0x92, 0xe2, 0xdf, 0x59, 0x8a, 0x22, 0x47, 0xb2, 0xb5, 0xa6, 0xed, 0x58, 0xd9, 0x24,
+	0x88, 0x93, 0x20, 0x74, 0xa2, 0xc4, 0x69, 0xe3, 0xa0, 0x0d, 0x28, 0x89, 0xae, 0xd8, 0x38, 0xb6,
+	0x3a, 0x94, 0x52, 0x38, 0x37, 0x8b, 0xd5, 0xee, 0x90, 0x5a, 0x64, 0xb9, 0xcb, 0xec, 0xcc, 0x06,
+	0x52, 0xfa, 0x1a, 0x45, 0xaf, 0x7a, 0xd1, 0x17, 0xe8, 0x55, 0x1f, 0xa0, 0x48, 0x7b, 0x11, 0xf4,
+	0x21, 0x7a, 0xd9, 0x57, 0x68, 0xd1, 0xbb, 0x62, 0xfe, 0xf6, 0x87, 0x94, 0xed, 0x18, 0x08, 0xd0,
+	0xdc, 0x71, 0xbe, 0xf3, 0x9d, 0xb3, 0x33, 0x67, 0xce, 0x9c, 0x73, 0x66, 0x08, 0x6d, 0x1a, 0xc5,
+	0x73, 0xe2, 0x0f, 0x16, 0x49, 0xcc, 0x62, 0x54, 0x97, 0xa3, 0xfe, 0x9d, 0x59, 0x1c, 0xcf, 0x42,
+	0x72, 0x4f, 0xa0, 0x67, 0xe9, 0xf4, 0x1e, 0x0b, 0xe6, 0x84, 0x32, 0x77, 0xbe, 0x90, 0x44, 0xfb,
+	0xef, 0x06, 0x34, 0x0e, 0xe2, 0xc8, 0x23, 0x0b, 0x86, 0x3a, 0x50, 0x09, 0x7c, 0xcb, 0xd8, 0x35,
+	0xee, 0x56, 0x71, 0x25, 0xf0, 0xd1, 0x10, 0x3a, 0x64, 0x3a, 0x25, 0x1e, 0x0b, 0xbe, 0x21, 0x0e,
+	0x57, 0xb4, 0x2a, 0xbb, 0xc6, 0x5d, 0x73, 0xaf, 0x3f, 0x90, 0x56, 0x07, 0xda, 0xea, 0xe0, 0x44,
+	0x5b, 0xc5, 0x1b, 0x99, 0x06, 0xc7, 0xd0, 0x75, 0xa8, 0xbb, 0x62, 0x64, 0x55, 0x77, 0x8d, 0xbb,
+	0x4d, 0xac, 0x46, 0xe8, 0x26, 0xb4, 0xe6, 0xb1, 0x9f, 0x86, 0xc4, 0x09, 0x7c, 0x6b, 0x5d, 0x7c,
+	0xb1, 0x29, 0x81, 0xb1, 0x8f, 0xde, 0x83, 0x6d, 0x9f, 0x4c, 0x83, 0x28, 0x60, 0x41, 0x1c, 0x39,
+	0x94, 0xb9, 0x2c, 0xa5, 0x9c, 0x57, 0x13, 0x3c, 0x94, 0xcb, 0x26, 0x42, 0x34, 0xf6, 0xed, 0xbf,
+	0x54, 0xc0, 0x3c, 0x24, 0xd4, 0x4b, 0x82, 0x05, 0xc7, 0x7f, 0x32, 0x2b, 0xb9, 0x0d, 0xe0, 0x49,
+	0xe7, 0xe6, 0xf3, 0x6f, 0x29, 0x64, 0xec, 0xa3, 0xd7, 0x60, 0x23, 0x74, 0xa3, 0x59, 0xea, 0xce,
+	0x88, 0xe3, 0xc5, 0x3e, 0xb1, 0xea, 0xbb, 0xc6, 0xdd, 0x16, 0x6e, 0x6b, 0xf0, 0x20, 0xf6, 0x09,
+	0xda, 0x81, 0x06, 0xbb, 0x5c, 0x08, 0xf3, 0x0d, 0x61, 0xa0, 0xce, 0x87, 0x63, 0x1f, 0x21, 0x58,
+	0x67, 0x24, 0x99, 0x5b, 0x4d, 0xa1, 0x24, 0x7e, 0xa3, 0x77, 0xa0, 0xe7, 0xb9, 0x94, 0x38, 0x34,
+	0x98, 0x45, 0xc1, 0x34, 0xf0, 0xdc, 0xc8, 0x23, 0x56, 0x4b, 0xa8, 0x75, 0xb9, 0x60, 0x52, 0xc0,
+	0xed, 0xff, 0x56, 0xa0, 0x8d, 0x49, 0xe8, 0x72, 0x97, 0xd1, 0xf3, 0x60, 0xf1, 0x93, 0x71, 0xdb,
+	0x4d, 0x68, 0xd1, 0x38, 0x4d, 0x3c, 0x92, 0x7b, 0xad, 0x29, 0x81, 0xb1, 0x8f, 0xde, 0x80, 0x8e,
+	0x4f, 0x28, 0x0b, 0x22, 0x31, 0x6f, 0xce, 0xa8, 0x0b, 0xc6, 0x46, 0x01, 0x1d, 0xfb, 0xe8, 0x5d,
+	0x40, 0x49, 0x61, 0x6d, 0xce, 0x2c, 0x89, 0xd3, 0x85, 0xf2, 0x60, 0xaf, 0x28, 0xf9, 0x15, 0x17,
+	0x14, 0xbd, 0xdc, 0x2c, 0x79, 0xf9, 0x43, 0xb8, 0xee, 0x9d, 0xbb, 0x89, 0xeb, 0x31, 0x92, 0x04,
+	0x94, 0x05, 0x9e, 0xa3, 0x79, 0xd2, 0xad, 0xdb, 0x65, 0xe9, 0x89, 0xd4, 0xba, 0x03, 0xe6, 0x3c,
+	0xf6, 0x83, 0x69, 0x40, 0x12, 0x4e, 0x05, 0x41, 0x05, 0x0d, 0x8d, 0x7d, 0xfb, 0xbb, 0x75, 0xe8,
+	0x62, 0x32, 0x25, 0x09, 0x89, 0x3c, 0x32, 0x21, 0x6c, 0xcc, 0xc8, 0xbc, 0xe0, 0xff, 0xd6, 0xff,
+	0xdb, 0xff, 0x09, 0x99, 0x52, 0x52, 0x88, 0xda, 0xa6, 0x04, 0xc6, 0x3e, 0xfa, 0x08, 0x76, 0x12,
+	0x3d, 0x71, 0xdf, 0xf1, 0xe2, 0xf9, 0x22, 0x8e, 0x48, 0xc4, 0xf2, 0x8d, 0xb8, 0x96, 0x8b, 0x0f,
+	0xb4, 0x74, 0xec, 0xa3, 0x09, 0xf4, 0x94, 0x51, 0x5f, 0x9d, 0xd4, 0x38, 0x11, 0xfb, 0x61, 0xee,
+	0xbd, 0x3e, 0x50, 0xc9, 0x0b, 0x93, 0xe9, 0x84, 0xb0, 0xc3, 0x4c, 0x5e, 0xf4, 0xd0, 0xd1, 0x1a,
+	0xee, 0x4a, 0x03, 0xb9, 0x1c, 0x7d, 0x08, 0x75, 0x1a, 0xcc, 0x17, 0x21, 0xb1, 0x9a, 0xca, 0x33,
+	0xca, 0xd2, 0x44, 0xa0, 0x4b, 0xfa, 0x8a, 0x8b, 0x1e, 0x40, 0x53, 0x1f, 0x31, 0xb1, 0x8b, 0xe6,
+	0xde, 0x2d, 0xad, 0xf7, 0x48, 0xe1, 0x4b, 0x9a, 0x19, 0x1f, 0xfd, 0x12, 0x40, 0x5a, 0x71, 0xe6,
+	0xee, 0x42, 0x6c, 0xac, 0xb9, 0x77, 0xbb, 0xfc, 0xd5, 0xcf, 0xdd, 0xc5, 0x92, 0x7a, 0x8b, 0x6a,
+	0x01, 0x1a, 0x82, 0xc9, 0x7d, 0x16, 0x92, 0x0b, 0x61, 0xc0, 0x14, 0x06, 0x5e, 0xd1, 0x06, 0x0e,
+	0xa4, 0x68, 0xd5, 0x02, 0x78, 0x99, 0x64, 0xbf, 0x0e, 0xeb, 0x67, 0xb1, 0x7f, 0x69, 0xff, 0xd9,
+	0x80, 0x5b, 0xcf, 0xf3, 0x18, 0xfa, 0x39, 0x58, 0x2e, 0x63, 0x49, 0x70, 0x96, 0x32, 0x92, 0x79,
+	0x5d, 0x1d, 0x1a, 0x79, 0xca, 0xaf, 0x67, 0xf2, 0x42, 0xfa, 0x1c, 0xfb, 0xe8, 0x6d, 0xe8, 0xe5,
+	0x9a, 0x3a, 0xe0, 0x2b, 0x42, 0x65, 0x33, 0x13, 0xa8, 0x58, 0x7f, 0x13, 0x72, 0xc8, 0x89, 0x13,
+	0x9f, 0x24, 0x22, 0xd6, 0x36, 0x70, 0x27, 0x83, 0x9f, 0x70, 0xd4, 0xde, 0x06, 0xb4, 0xba, 0x2d,
+	0xf6, 0x10, 0xb6, 0xaf, 0x72, 0x3a, 0x7a, 0x0b, 0xba, 0xae, 0xc7, 0x13, 0xa5, 0x7b, 0x16, 0x84,
+	0x01, 0xbb, 0xcc, 0x27, 0xbd, 0x59, 0xc2, 0xc7, 0xbe, 0xfd, 0x11, 0x5c, 0xbb, 0xd2, 0xf3, 0x3c,
+	0xff, 0xce, 0xdd, 0x85, 0xc3, 0xdc, 0x64, 0x46, 0x98, 0x3a, 0x58, 0xad, 0xb9, 0xbb, 0x38, 0x11,
+	0x80, 0xfd, 0x6f, 0x03, 0xae, 0x5f, 0xed, 0x71, 0x71, 0x3e, 0x5c, 0x9d, 0x35, 0x0c, 0x75, 0x3e,
+	0x5c, 0x95, 0x2c, 0x5e, 0x85, 0x36, 0x17, 0x2e, 0x92, 0x20, 0x4e, 0x02, 0x76, 0xa9, 0x1c, 0x63,
+	0xce, 0xdd, 0xc5, 0xb1, 0x82, 0xd0, 0x0d, 0xe0, 0x74, 0x27, 0x49, 0x43, 0x79, 0xf2, 0x5a, 0xb8,
+	0x31, 0x77, 0x17, 0x38, 0x0d, 0x89, 0x9e, 0x94, 0xeb, 0x7f, 0x13, 0x78, 0xc4, 0x5a, 0xcf, 0x26,
+	0x35, 0x14, 0xc0, 0xd2, 0x9c, 0x6b, 0x4b, 0x73, 0x46, 0xbb, 0x3c, 0x7e, 0x12, 0x9d, 0xc0, 0xd4,
+	0x91, 0x2b, 0x42, 0x7a, 0x76, 0x9e, 0xcb, 0xc8, 0x2c, 0x4e, 0x2e, 0xad, 0x46, 0x36, 0xbb, 0x03,
+	0x05, 0xd9, 0xff, 0xa8, 0xc0, 0xe6, 0xe8, 0x82, 0x91, 0xc8, 0xe7, 0x67, 0x54, 0x56, 0xff, 0xb7,
+	0xa0, 0xa1, 0x2a, 0x93, 0x58, 0xaf, 0xb9, 0xb7, 0x99, 0x07, 0xa5, 0x80, 0xb1, 0x96, 0xa3, 0x07,
+	0xb0, 0x51, 0xcc, 0xa0, 0xd4, 0xaa, 0xec, 0x56, 0xef, 0x9a, 0x7b, 0xdb, 0xf9, 0x31, 0xce, 0x85,
+	0xb8, 0x4c, 0x45, 0x47, 0x70, 0x6d, 0x21, 0x12, 0x44, 0x42, 0xfc, 0x62, 0x4c, 0x0a, 0x2f, 0x99,
+	0x7b, 0x5b, 0xda, 0x46, 0x21, 0x1e, 0xf1, 0x76, 0xa6, 0x51, 0x40, 0x79, 0x9b, 0x90, 0x10, 0x2f,
+	0x4d, 0x28, 0xcf, 0x8e, 0x0b, 0x37, 0x91, 0x49, 0x88, 0x5a, 0xeb, 0xbb, 0x55, 0xde, 0x26, 0x64,
+	0xb2, 0x63, 0x21, 0x1a, 0xfb, 0x94, 0x47, 0xb5, 0x1f, 0x24, 0xc4, 0x63, 0x45, 0x7a, 0x4d, 0xd0,
+	0x37, 0xa5, 0x20, 0xe7, 0xbe, 0x09, 0x9b, 0xba, 0x74, 0xcb, 0xac, 0x43, 0xad, 0xba, 0x60, 0x76,
+	0x14, 0x8c, 0x25, 0x6a, 0x7f, 0x5f, 0x85, 0x2d, 0xed, 0xcb, 0xe2, 0xf4, 0xee, 0x83, 0x59, 0x5c,
+	0x9e, 0xf1, 0xec, 0xe5, 0x15, 0x79, 0xc5, 0x6d, 0xa8, 0xbe, 0x60, 0x1b, 0x9e, 0xe9, 0xca, 0xf5,
+	0x1f, 0xcb, 0x95, 0xb5, 0x97, 0x73, 0x65, 0xfd, 0x07, 0xbb, 0xb2, 0x71, 0x95, 0x2b, 0xd1, 0x3d,
+	0xd8, 0x2a, 0x66, 0x29, 0x4d, 0x6e, 0xca, 0x59, 0x14, 0x44, 0x5a, 0xe1, 0x55, 0x68, 0xe7, 0x1e,
+	0x08, 0x22, 0xab, 0x25, 0x98, 0x66, 0x86, 0x8d, 0x23, 0xde, 0x63, 0xe9, 0x74, 0xc1, 0x8b, 0x5d,
+	0x64, 0x81, 0xe0, 0xb4, 0x73, 0x70, 0x1c, 0xfd, 0x7a, 0xbd, 0x59, 0xe9, 0x56, 0xed, 0xbf, 0xd5,
+	0x01, 0x46, 0x17, 0x8b, 0x84, 0x50, 0xca, 0x9d, 0x72, 0x0f, 0x6a, 0xbc, 0xa7, 0xa2, 0x96, 0x21,
+	0xa2, 0xfb, 0x86, 0x76, 0x67, 0x4e, 0x19, 0x1c, 0x84, 0x6e, 0x4a, 0x09, 0x96, 0x3c, 0x74, 0x0c,
+	0xbd, 0x95, 0xbe, 0x55, 0xe4, 0x86, 0xce, 0xde, 0x6b, 0x57, 0x28, 0x1f, 0x2e, 0xf5, 0xb1, 0xb8,
+	0xbb, 0xdc, 0xd9, 0xf6, 0xff, 0x55, 0x01, 0xc0, 0x1c, 0x24, 0x73, 0x12, 0x31, 0xf4, 0x2e, 0xb4,
+	0xb2, 0x94, 0xfa, 0xac, 0x43, 0x9a, 0x33, 0xd0, 0x47, 0xb0, 0xa1, 0xfd, 0xfe, 0x8d, 0x1b, 0xa6,
+	0xba, 0x7b, 0x58, 0x56, 0x39, 0x5a, 0xc3, 0x6d, 0xc5, 0xfb, 0x82, 0xd3, 0xd0, 0x6b, 0xd0, 0xa6,
+	0x2c, 0x09, 0xa2, 0x99, 0x52, 0x13, 0xf9, 0xeb, 0x68, 0x0d, 0x9b, 0x12, 0x95, 0xa4, 0xdb, 0xd0,
+	0x0a, 0x22, 0x6d, 0x58, 0x34, 0x10, 0xbc, 0x4c, 0x06, 0x51, 0x6e, 0xc3, 0x8f, 0x53, 0xee, 0x72,
+	0xc9, 0xe0, 0x79, 0xcc, 0xe0, 0x36, 0x24, 0x2a, 0x49, 0x9f, 0x42, 0x97, 0x64, 0xfe, 0x50, 0x44,
+	0xd9, 0x11, 0xa0, 0x55, 0x7f, 0x1d, 0xad, 0xe1, 0xcd, 0x9c, 0x2d, 0x0d, 0xfc, 0x02, 0x20, 0xc9,
+	0xdc, 0x63, 0xd5, 0xcb, 0xc5, 0xb8, 0xe0, 0xea, 0xdc, 0x87, 0xb8, 0xa0, 0xb0, 0xdf, 0x80, 0x9a,
+	0xf8, 0x68, 0xff, 0x18, 0x36, 0x73, 0x8a, 0xcc, 0xf1, 0x65, 0xd3, 0x32, 0x04, 0x7e, 0xb8, 0xe9,
+	0xfe, 0x5f, 0x0d, 0xa8, 0xcb, 0xe8, 0x78, 0x99, 0xc4, 0x3a, 0x86, 0x8e, 0xb4, 0xe1, 0xcb, 0xca,
+	0xa3, 0x33, 0xab, 0xfd, 0xdc, 0x0f, 0x8b, 0x09, 0xf3, 0x3c, 0x2b, 0x34, 0xc5, 0x88, 0xa2, 0x4f,
+	0xc1, 0xcc, 0xa7, 0x43, 0xad, 0xea, 0x0f, 0x59, 0x40, 0x51, 0xc3, 0xbe, 0x0f, 0xdd, 0xe5, 0x08,
+	0x45, 0x3d, 0xd8, 0x18, 0xfd, 0xe6, 0x74, 0xfc, 0xc5, 0xf0, 0xd1, 0xe8, 0xf1, 0x89, 0x73, 0xf2,
+	0xa4, 0xbb, 0x86, 0x3a, 0x00, 0x93, 0xd3, 0xfd, 0x93, 0xa7, 0xc7, 0x23, 0xe7, 0xc9, 0xc3, 0xae,
+	0x61, 0xff, 0xc9, 0x00, 0x34, 0x49, 0xcf, 0x68, 0x3a, 0x57, 0x27, 0xf5, 0xeb, 0x94, 0x50, 0xc6,
+	0xfb, 0x50, 0x7a, 0x49, 0x19, 0x99, 0xab, 0x2a, 0xac, 0x46, 0xe8, 0x1a, 0xd4, 0xf9, 0xcd, 0xc7,
+	0x71, 0x55, 0x11, 0xad, 0xf1, 0xd1, 0x30, 0x83, 0xcf, 0xac, 0x6a, 0x0e, 0xef, 0xf3, 0xf3, 0x5e,
+	0x08, 0x18, 0x57, 0x15, 0x4f, 0x33, 0xc7, 0x86, 0x4b, 0x94, 0x33, 0xab, 0xb6, 0x4c, 0xd9, 0xb7,
+	0xff, 0x68, 0xc0, 0x56, 0x69, 0x8a, 0x74, 0x11, 0x47, 0x94, 0xb7, 0x85, 0xf5, 0x84, 0xd0, 0x34,
+	0x94, 0xfb, 0xd4, 0xc9, 0xbd, 0x7e, 0x05, 0x79, 0x80, 0x05, 0x13, 0x2b, 0x0d, 0x7b, 0x0c, 0x75,
+	0x89, 0x70, 0x87, 0xe4, 0x3e, 0xea, 0xae, 0xa1, 0x36, 0x34, 0x27, 0xa7, 0xfb, 0x93, 0xd3, 0xcf,
+	0x47, 0x93, 0xae, 0x81, 0x36, 0xc1, 0x54, 0xa3, 0x43, 0x67, 0xff, 0x69, 0xb7, 0x82, 0xba, 0xd0,
+	0x7e, 0xfc, 0xe4, 0xc4, 0xd1, 0x60, 0xb7, 0x6a, 0x1f, 0x41, 0xef, 0x20, 0x8e, 0x28, 0x4b, 0xdc,
+	0x20, 0x62, 0xda, 0x7f, 0x5d, 0xa8, 0x12, 0x2f, 0x54, 0xce, 0xe3, 0x3f, 0x65, 0x99, 0xbf, 0x08,
+	0xe6, 0xe9, 0xdc, 0x39, 0x0f, 0x98, 0x4c, 0x34, 0x35, 0x6c, 0x2a, 0xec, 0x28, 0x60, 0xd4, 0xfe,
+	0x0c, 0x50, 0xd1, 0x92, 0x5a, 0xe6, 0x1d, 0x30, 0x55, 0xbc, 0x89, 0xa4, 0x6d, 0x88, 0x7c, 0x08,
+	0xd9, 0xad, 0x94, 0xa2, 0x6d, 0xa8, 0xb1, 0x98, 0xb9, 0xa1, 0xde, 0x12, 0x31, 0xb0, 0x3f, 0x80,
+	0x5e, 0x1e, 0x35, 0x7a, 0x5a, 0xaf, 0x00, 0xe4, 0x9e, 0x55, 0xb3, 0x2b, 0x20, 0xf6, 0x63, 0xe8,
+	0x9e, 0x24, 0x6e, 0x44, 0x43, 0x97, 0x11, 0xad, 0x53, 0xbe, 0x14, 0x1b, 0xcb, 0x97, 0xe2, 0x9b,
+	0xd0, 0x92, 0xbd, 0x4f, 0xde, 0x72, 0x36, 0x25, 0x30, 0xf6, 0xed, 0xdf, 0x1b, 0xd0, 0x2b, 0x18,
+	0x54, 0x2b, 0x7a, 0xe7, 0x45, 0x27, 0xec, 0x68, 0xad, 0x58, 0x35, 0x51, 0x76, 0x41, 0x71, 0xc4,
+	0x1d, 0x87, 0x47, 0xa5, 0x4c, 0x8d, 0x56, 0xe1, 0x22, 0x52, 0xba, 0x9a, 0xa9, 0xcb, 0x47, 0x09,
+	0xdb, 0x6f, 0xea, 0x78, 0xb1, 0xff, 0x63, 0xc2, 0xc6, 0x84, 0xb8, 0x89, 0x77, 0x5e, 0x8c, 0x77,
+	0x01, 0x64, 0xf1, 0x2e, 0x46, 0xcf, 0xac, 0xb4, 0x95, 0x97, 0xab, 0xb4, 0xd5, 0xab, 0x2b, 0xed,
+	0xdb, 0xd0, 0xcb, 0x66, 0x29, 0xd7, 0x96, 0xf5, 0x43, 0x9b, 0xa5, 0xe9, 0xfb, 0x74, 0x25, 0x7e,
+	0x6a, 0x2b, 0xf1, 0xc3, 0x5b, 0xf0, 0x20, 0xf2, 0xc2, 0xd4, 0xe7, 0x85, 0x53, 0x5d, 0x23, 0xeb,
+	0xe2, 0x1a, 0xb9, 0xa9, 0xf0, 0xb1, 0x82, 0xd1, 0xfb, 0x50, 0x9b, 0xa6, 0xdf, 0x7e, 0x2b, 0xbb,
+	0xcd, 0xce, 0xde, 0xcd, 0xec, 0xe8, 0x14, 0xbd, 0x32, 0x78, 0xc8, 0x29, 0x58, 0x32, 0xf9, 0x0d,
+	0x5d, 0x16, 0x61, 0xe2, 0x3b, 0xfa, 0x7a, 0x45, 0xd5, 0x6b, 0x46, 0x4f, 0x4b, 0xf4, 0xd5, 0x80,
+	0x72, 0x8f, 0xc6, 0x53, 0x5e, 0xf6, 0xc5, 0x95, 0xad, 0x86, 0xd5, 0x88, 0xe3, 0xdc, 0x67, 0x71,
+	0x22, 0x2e, 0x63, 0x2d, 0xac, 0x46, 0xe8, 0x16, 0xb4, 0xce, 0x83, 0xd9, 0x79, 0x18, 0xcc, 0xce,
+	0x99, 0xb8, 0x66, 0x35, 0x71, 0x0e, 0xa0, 0x0f, 0xa0, 0x3e, 0x75, 0x3d, 0xc2, 0xa8, 0xd5, 0xde,
+	0xad, 0x3e, 0x67, 0xc2, 0x9c, 0x83, 0x15, 0x15, 0xdd, 0x87, 0x1d, 0xf1, 0xcb, 0x59, 0x75, 0xf2,
+	0x86, 0x70, 0xf2, 0xb6, 0x10, 0xe3, 0x25, 0x4f, 0x0f, 0xe1, 0xb6, 0x76, 0xe3, 0x34, 0x0d, 0xc3,
+	0x4b, 0x87, 0x2e, 0x88, 0x17, 0x4c, 0x03, 0xe2, 0x3b, 0x91, 0x3b, 0x27, 0xd4, 0xea, 0x88, 0xd9,
+	0xf5, 0x15, 0xe9, 0x21, 0xe7, 0x4c, 0x34, 0xe5, 0x31, 0x67, 0xf0, 0x9d, 0x20, 0x17, 0xd2, 0x04,
+	0xbd, 0x8c, 0xe2, 0xe8, 0x72, 0x4e, 0xad, 0x4d, 0xb9, 0x13, 0x0a, 0x9f, 0x28, 0x98, 0x37, 0x51,
+	0x9a, 0x9a, 0xf7, 0x13, 0xd4, 0xea, 0x0a, 0x36, 0x52, 0xa2, 0x3c, 0xb3, 0x53, 0xf4, 0x09, 0xf4,
+	0xb3, 0x57, 0xa8, 0xd5, 0x85, 0xf5, 0xc4, 0xc2, 0x76, 0xc2, 0x2b, 0xae, 0x68, 0x7c, 0x6d, 0xfc,
+	0xc1, 0x29, 0x0e, 0x43, 0x77, 0x41, 0x89, 0xa3, 0x4e, 0x18, 0xb5, 0x90, 0xf8, 0x56, 0x57, 0x0b,
+	0xd4, 0x21, 0xa4, 0xa8, 0x0f, 0xcd, 0xc5, 0x79, 0x1c, 0x11, 0x16, 0x78, 0xd6, 0x96, 0xe0, 0x64,
+	0x63, 0xf4, 0x33, 0x68, 0x24, 0x6e, 0xf4, 0x55, 0x10, 0xcd, 0xac, 0xed, 0xa5, 0x4b, 0x75, 0x69,
+	0x47, 0xb0, 0x24, 0x61, 0xcd, 0xe6, 0xde, 0x5d, 0x6a, 0x1a, 0x97, 0x56, 0x70, 0x4d, 0xac, 0xa0,
+	0x5f, 0x6e, 0x1f, 0x4b, 0x8b, 0xe0, 0x57, 0x2e, 0xfd, 0x18, 0x42, 0xad, 0xeb, 0x82, 0xdf, 0xd2,
+	0xaf, 0x21, 0x14, 0x7d, 0x0c, 0x37, 0xb4, 0x47, 0x57, 0xad, 0xef, 0x08, 0xf6, 0x75, 0x45, 0x58,
+	0xb6, 0x6c, 0x41, 0x83, 0x5c, 0x2c, 0x42, 0x37, 0x88, 0x2c, 0x4b, 0x2c, 0x58, 0x0f, 0x75, 0x42,
+	0xbf, 0x91, 0x27, 0xf4, 0xa5, 0xbc, 0xdc, 0x5f, 0xc9, 0xcb, 0x16, 0x34, 0x68, 0x3a, 0x9b, 0x11,
+	0xca, 0xac, 0x9b, 0xd2, 0x98, 0x1a, 0xa2, 0x07, 0x70, 0x43, 0x47, 0xd8, 0x72, 0x76, 0xa1, 0xd6,
+	0x2d, 0xc1, 0xdd, 0x51, 0x04, 0x5c, 0xce, 0x30, 0xb4, 0xff, 0xbd, 0x01, 0x0d, 0xe5, 0x54, 0x7e,
+	0x66, 0xb2, 0xde, 0x59, 0x24, 0xae, 0x0a, 0xce, 0x01, 0x5e, 0x17, 0xc8, 0x85, 0xeb, 0x31, 0x91,
+	0x2c, 0x2b, 0x58, 0x0e, 0xf8, 0xf9, 0xe3, 0x94, 0xe0, 0x42, 0x94, 0xea, 0x0a, 0x56, 0x23, 0xbe,
+	0x1c, 0x7a, 0x1e, 0x27, 0xcc, 0x91, 0x4d, 0xf4, 0xba, 0x10, 0x82, 0x80, 0x4e, 0x38, 0xc2, 0x3b,
+	0xf3, 0x92, 0x3b, 0x45, 0x06, 0xaa, 0xe0, 0x76, 0x31, 0x51, 0x5d, 0x9d, 0xd1, 0xea, 0x57, 0x66,
+	0x34, 0xfb, 0x13, 0xa8, 0x89, 0x04, 0x83, 0x10, 0x74, 0x1e, 0x0e, 0x1f, 0x3d, 0xda, 0x1f, 0x1e,
+	0x7c, 0xe6, 0x3c, 0x3c, 0xfd, 0xf2, 0xcb, 0xa7, 0xdd, 0x35, 0x5e, 0x67, 0x87, 0x8f, 0x7e, 0x3b,
+	0x7c, 0x3a, 0x51, 0x88, 0xc1, 0x0b, 0xf3, 0xe3, 0x27, 0x6a, 0x54, 0xb1, 0xef, 0x43, 0x4d, 0x1c,
+	0x76, 0xb4, 0x01, 0xad, 0xa3, 0xf1, 0x08, 0x0f, 0xf1, 0xc1, 0x11, 0xd7, 0x03, 0xa8, 0x7f, 0xfe,
+	0xe4, 0xf0, 0xf4, 0xd1, 0xa8, 0x6b, 0xf0, 0xf6, 0x07, 0x8f, 0x1e, 0x8e, 0xf0, 0xe8, 0xf1, 0xc1,
+	0xc8, 0x99, 0x8c, 0x4e, 0xba, 0x15, 0xfb, 0x0f, 0x75, 0xe8, 0xe8, 0x00, 0x55, 0xd5, 0xe8, 0x7d,
+	0xa8, 0xf1, 0x92, 0xa2, 0xef, 0x0d, 0x2b, 0x99, 0x45, 0xd2, 0x06, 0xbc, 0x84, 0x60, 0xc9, 0xe4,
+	0x01, 0x28, 0x8a, 0x6c, 0x5e, 0xc9, 0xab, 0xb8, 0x25, 0x10, 0x91, 0x87, 0xef, 0x80, 0x19, 0x91,
+	0x0b, 0xe6, 0xa8, 0x3c, 0x27, 0xdf, 0x13, 0x80, 0x43, 0x07, 0x02, 0xe1, 0x9d, 0x8b, 0xca, 0x66,
+	0xeb, 0xe5, 0x7e, 0x71, 0xe9, 0x9b, 0x32, 0x9d, 0xa9, 0xce, 0x45, 0x6a, 0xf0, 0x12, 0xae, 0xc2,
+	0x88, 0x97, 0x70, 0xd9, 0x2e, 0x15, 0x10, 0x74, 0x08, 0xa6, 0x88, 0xd9, 0x28, 0x7f, 0x70, 0x78,
+	0xf6, 0x07, 0x46, 0x39, 0x13, 0x17, 0xd5, 0xfa, 0xff, 0x34, 0x60, 0x9d, 0xaf, 0x38, 0x7b, 0xb5,
+	0x36, 0x0a, 0xaf, 0xd6, 0xe5, 0x8e, 0xa0, 0xb2, 0xdc, 0x11, 0xbc, 0x01, 0x9d, 0xfc, 0x96, 0x27,
+	0x94, 0xa5, 0x07, 0x36, 0x32, 0x94, 0x07, 0x14, 0x0f, 0x4f, 0xea, 0xc5, 0x89, 0xbc, 0x8d, 0x18,
+	0x58, 0x0e, 0xd4, 0x73, 0x71, 0xf1, 0xe5, 0xab, 0x96, 0x3d, 0x17, 0x17, 0x1e, 0xbc, 0x4a, 0xd5,
+	0x42, 0x3e, 0xc3, 0xe7, 0x00, 0x7f, 0x74, 0x29, 0xfa, 0xa0, 0x91, 0xf5, 0x94, 0xd9, 0xfa, 0x3e,
+	0x01, 0xb3, 0xb0, 0x76, 0x7e, 0x28, 0x58, 0xfc, 0x15, 0x89, 0x64, 0x10, 0xb4, 0xb0, 0x1a, 0xf1,
+	0x39, 0x7e, 0x9d, 0x92, 0x44, 0x3e, 0x19, 0xb5, 0xb0, 0x1c, 0xf4, 0x7f, 0x07, 0x66, 0x61, 0x67,
+	0x44, 0x2d, 0xe5, 0x43, 0xd5, 0x86, 0x3e, 0xb7, 0x34, 0x49, 0x26, 0xfa, 0x98, 0xf7, 0xcb, 0x69,
+	0xc4, 0xf4, 0x85, 0xe1, 0xd5, 0xe7, 0x05, 0xc0, 0x01, 0x67, 0x62, 0xa5, 0xd0, 0x3f, 0x05, 0xc8,
+	0xd1, 0x17, 0x35, 0x67, 0x08, 0xd6, 0x79, 0xc9, 0x52, 0xd3, 0x17, 0xbf, 0xf9, 0x9a, 0x84, 0xa9,
+	0xbc, 0x55, 0x4f, 0x23, 0x66, 0x6f, 0x41, 0x8f, 0x5f, 0x1a, 0xc4, 0xa3, 0x38, 0x55, 0x73, 0xb6,
+	0xbf, 0xab, 0x01, 0xe4, 0x28, 0xaf, 0x07, 0x59, 0xcd, 0x50, 0x6f, 0x6c, 0x7a, 0x2c, 0x5e, 0x15,
+	0xe5, 0xc3, 0x77, 0x46, 0x91, 0x81, 0xd1, 0x91, 0x70, 0x56, 0x54, 0x6c, 0x68, 0x17, 0xb6, 0x92,
+	0xaa, 0x59, 0x94, 0x30, 0x5e, 0x13, 0x95, 0xb1, 0x12, 0x55, 0xbe, 0x7b, 0x23, 0x29, 0x3a, 0x2c,
+	0x2a, 0xbc, 0xbe, 0xfc, 0xc2, 0xa5, 0x82, 0xa6, 0x04, 0xa2, 0xf7, 0x61, 0x5b, 0x99, 0x2d, 0x93,
+	0xe5, 0xa3, 0x9c, 0xfa, 0x24, 0x2e, 0xa9, 0x0c, 0x60, 0x6b, 0xb5, 0xfb, 0xa4, 0xf9, 0xff, 0x12,
+	0xe5, 0x16, 0x53, 0x14, 0xe7, 0xec, 0x13, 0xab, 0x6a, 0xf2, 0xaf, 0x8a, 0x1d, 0xfd, 0xa1, 0x65,
+	0xe5, 0x3d, 0x68, 0xc8, 0x2a, 0x46, 0xc5, 0xcb, 0x48, 0xa1, 0xbf, 0xcd, 0x37, 0x61, 0x20, 0xa3,
+	0x41, 0x13, 0xd1, 0x03, 0x30, 0xcf, 0x03, 0x92, 0xf0, 0xa8, 0x09, 0x08, 0xb5, 0xe0, 0x05, 0x7a,
+	0x45, 0x32, 0x3a, 0x86, 0x9d, 0xab, 0x3b, 0x09, 0x6a, 0x99, 0x2f, 0xb0, 0x73, 0xed, 0xaa, 0x06,
+	0x83, 0xdf, 0x62, 0x3b, 0x4b, 0x86, 0xda, 0x2f, 0x30, 0xb4, 0x51, 0xf4, 0x21, 0xed, 0x1f, 0x43,
+	0xed, 0xc7, 0x0d, 0xec, 0xfd, 0xf7, 0xe0, 0x96, 0x17, 0xcf, 0x07, 0x24, 0xf4, 0x93, 0xe0, 0x62,
+	0xc0, 0xd3, 0x51, 0x10, 0xc5, 0x61, 0x3c, 0xbb, 0x1c, 0xcc, 0x63, 0x9f, 0x84, 0xfb, 0xf5, 0x63,
+	0xfe, 0xa7, 0x0c, 0x3d, 0x36, 0xbe, 0x54, 0x7f, 0xc2, 0x9e, 0xd5, 0xc5, 0xdf, 0x34, 0x1f, 0xfc,
+	0x6f, 0x00, 0xfa, 0x7f, 0x62, 0x9d, 0xa3, 0x1d, 0x00, 0x00,
 }
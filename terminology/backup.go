@@ -0,0 +1,274 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package terminology
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wardle/go-terminology/terminology/storage"
+	"github.com/wardle/go-terminology/terminology/storage/boltdb"
+)
+
+// Names of the entries within a backup archive
+const (
+	archiveStore      = "bolt.db"
+	archiveDescriptor = "sctdb.json"
+	archiveIndex      = "bleve_index"
+	archiveChecksums  = "SHA256SUMS"
+)
+
+// Backup writes a consistent snapshot of the persistence store, together with
+// the search index and datastore descriptor, to the writer as a gzip compressed
+// tar archive. The archive ends with a manifest of SHA-256 checksums for each
+// file which is verified by Restore. A search index held in memory is not
+// included, and must be rebuilt once the archive is restored.
+func (svc *Svc) Backup(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	checksums := make(map[string]string)
+
+	// take a hot copy of the store into a temporary file, as tar needs to know its size in advance
+	tmp, err := ioutil.TempFile("", "sctdb-backup")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if _, err := svc.Store.Backup(tmp); err != nil {
+		return err
+	}
+	if err := archiveFile(tw, tmp.Name(), archiveStore, checksums); err != nil {
+		return err
+	}
+	if err := archiveFile(tw, filepath.Join(svc.path, archiveDescriptor), archiveDescriptor, checksums); err != nil {
+		return err
+	}
+	if svc.index.inMemory {
+		log.Printf("search index is held in memory and so is not included in the backup; rebuild the index after restoring")
+	} else if err := archiveIndexSnapshot(tw, svc.index, checksums); err != nil {
+		return err
+	}
+
+	// finally, write the manifest of checksums
+	var manifest strings.Builder
+	for name, sum := range checksums {
+		fmt.Fprintf(&manifest, "%s  %s\n", sum, name)
+	}
+	hdr := &tar.Header{Name: archiveChecksums, Mode: 0644, Size: int64(manifest.Len()), ModTime: time.Now()}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(tw, manifest.String()); err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// archiveIndexSnapshot adds a consistent snapshot of the current search index to the archive
+func archiveIndexSnapshot(tw *tar.Writer, index *switchableSearch, checksums map[string]string) error {
+	tmp, err := ioutil.TempDir("", "sctdb-backup-index")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	indexPath := filepath.Join(tmp, archiveIndex)
+	if err := index.snapshot(indexPath); err != nil {
+		return err
+	}
+	return filepath.Walk(indexPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(indexPath, path)
+		if err != nil {
+			return err
+		}
+		return archiveFile(tw, path, filepath.ToSlash(filepath.Join(archiveIndex, rel)), checksums)
+	})
+}
+
+// archiveFile adds the file to the archive with the given name, recording its checksum
+func archiveFile(tw *tar.Writer, path string, name string, checksums map[string]string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tw, h), f); err != nil {
+		return err
+	}
+	checksums[name] = hex.EncodeToString(h.Sum(nil))
+	return nil
+}
+
+// restoreLockTimeout is how long Restore waits for any other process to close the datastore
+const restoreLockTimeout = time.Second
+
+// Restore extracts a backup archive created by Backup and, only once every
+// checksum has been verified, replaces the datastore at the path specified.
+// Any existing datastore is removed. The datastore must not be open, and the
+// restore fails if another process has it open.
+func Restore(r io.Reader, path string) error {
+	path = filepath.Clean(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0771); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(path), filepath.Base(path)+".restore")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	checksums, expected, err := extractArchive(r, tmp)
+	if err != nil {
+		return err
+	}
+	if expected == nil {
+		return fmt.Errorf("invalid archive: missing %s", archiveChecksums)
+	}
+	if len(expected) != len(checksums) {
+		return fmt.Errorf("invalid archive: %d files listed in %s but %d found", len(expected), archiveChecksums, len(checksums))
+	}
+	for name, sum := range expected {
+		if checksums[name] != sum {
+			return fmt.Errorf("invalid archive: checksum mismatch for %s", name)
+		}
+	}
+	if _, ok := checksums[archiveStore]; !ok {
+		return fmt.Errorf("invalid archive: missing %s", archiveStore)
+	}
+	// the index is always restored to its default location, so forget any rebuilt index
+	if desc, err := storage.ReadDescriptor(tmp); err == nil && desc.Index != "" {
+		desc.Index = ""
+		if err := desc.Save(); err != nil {
+			return err
+		}
+	}
+
+	// swap the restored data into place, keeping the original until successful
+	unlock, err := boltdb.Lock(path, restoreLockTimeout)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	old := path + ".old"
+	if err := os.RemoveAll(old); err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		if err := os.Rename(path, old); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Rename(old, path)
+		return err
+	}
+	return os.RemoveAll(old)
+}
+
+// extractArchive extracts the archive into the directory specified, returning the
+// checksums of the files extracted and those listed in the archive manifest.
+func extractArchive(r io.Reader, dir string) (checksums map[string]string, expected map[string]string, err error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	checksums = make(map[string]string)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Name == archiveChecksums {
+			if expected, err = readChecksums(tr); err != nil {
+				return nil, nil, err
+			}
+			continue
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if filepath.IsAbs(name) || strings.HasPrefix(filepath.Clean(name), "..") {
+			return nil, nil, fmt.Errorf("invalid archive: illegal path %s", hdr.Name)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		target := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(target), 0771); err != nil {
+			return nil, nil, err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode)&os.ModePerm)
+		if err != nil {
+			return nil, nil, err
+		}
+		h := sha256.New()
+		_, err = io.Copy(io.MultiWriter(f, h), tr)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		checksums[hdr.Name] = hex.EncodeToString(h.Sum(nil))
+	}
+	return checksums, expected, nil
+}
+
+// readChecksums parses a manifest of checksums in the format used by sha256sum
+func readChecksums(r io.Reader) (map[string]string, error) {
+	result := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.SplitN(scanner.Text(), "  ", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid archive: malformed %s", archiveChecksums)
+		}
+		result[fields[1]] = fields[0]
+	}
+	return result, scanner.Err()
+}
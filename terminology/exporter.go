@@ -92,5 +92,6 @@ func initialiseExtendedFromDescription(svc *Svc, ed *snomed.ExtendedDescription,
 		return err
 	}
 	ed.DescriptionRefsets = descRefsets
-	return nil
+	ed.PreferredIn, ed.AcceptableIn, err = svc.languageAcceptability(d.Id, descRefsets)
+	return err
 }
@@ -27,15 +27,18 @@ import (
 // This automatically clears the precomputations, if they exist, but does
 // not run precomputations at the end as the user may run multiple individual imports
 // from multiple SNOMED-CT distributions before finally running precomputations
-// at the end of multiple imports.
-func (svc *Svc) PerformImport(root string) {
+// at the end of multiple imports. Components that cannot be stored are logged and
+// the import continues, returning the first such error once complete.
+func (svc *Svc) PerformImport(root string) error {
 	logger := log.New(os.Stdout, "logger: ", log.Lshortfile)
 	concepts, descriptions, relationships, refsets := 0, 0, 0, 0
-	var err error
+	var importErr error
 	importer := snomed.NewImporter(logger, func(o interface{}) {
-		err = svc.Put(o)
-		if err != nil {
+		if err := svc.Put(o); err != nil {
 			logger.Printf("error importing : %v", err)
+			if importErr == nil {
+				importErr = err
+			}
 		} else {
 			switch o.(type) {
 			case []*snomed.Concept:
@@ -50,11 +53,11 @@ func (svc *Svc) PerformImport(root string) {
 		}
 	})
 	svc.ClearPrecomputations()
-	err = importer.ImportFiles(root)
-	if err != nil {
-		log.Fatalf("Could not import files: %v", err)
+	if err := importer.ImportFiles(root); err != nil {
+		return fmt.Errorf("could not import files: %w", err)
 	}
 	fmt.Printf("Imported %d concepts, %d descriptions, %d relationships and %d refsets\n", concepts, descriptions, relationships, refsets)
+	return importErr
 }
 
 // ClearPrecomputations clears all precached precomputations
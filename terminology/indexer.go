@@ -1,116 +1,391 @@
 package terminology
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/search"
+	"github.com/wardle/go-terminology/terminology/search/bleve"
+	"github.com/wardle/go-terminology/terminology/search/memory"
+	"github.com/wardle/go-terminology/terminology/storage"
+	"github.com/wardle/go-terminology/terminology/storage/boltdb"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/text/language"
 )
 
+// IndexOptions configures which concepts and descriptions are indexed, and how.
+// The zero value indexes every description of every concept.
+type IndexOptions struct {
+	Hierarchies   []int64             // only index concepts within these hierarchies, if specified
+	ReferenceSets []int64             // only index concepts that are members of these reference sets, if specified
+	Languages     []language.Tag      // only index descriptions in these languages, if specified
+	Workers       int                 // number of concurrent workers, defaulting to the number of CPUs
+	Progress      func(IndexProgress) // called after each batch of descriptions is indexed, if specified
+	Phonetic      bool                // whether to include a phonetic encoding of each term, when rebuilding the index
+}
+
+// IndexProgress reports the progress of indexing
+type IndexProgress struct {
+	Concepts     int // concepts processed
+	Descriptions int // descriptions indexed
+	Elapsed      time.Duration
+	Done         bool // whether indexing is complete
+}
+
+// indexBatchSize is the number of descriptions passed to the search service in each batch
+const indexBatchSize = 10000
+
 // Index orchestrates gouroutine build process of snomed.ExtendedDescription for
 // each concept in datastore and passes to the search service for indexing.
-func (svc *Svc) Index() error {
-	var (
-		wg          sync.WaitGroup
-		conceptsChn = make(chan snomed.Concept)
-		indexChn    = make(chan snomed.ExtendedDescription, 1000) // Create buffered channel
-	)
-	go func() {
-		var count = 0
-		svc.Iterate(func(concept *snomed.Concept) error {
-			conceptsChn <- *concept
-			count++
-			if count == 10000 {
-				return fmt.Errorf("Break")
+// The index is built in a temporary directory, flushed and closed, and only then
+// renamed and recorded in the datastore descriptor, replacing the previous index.
+// A failed build therefore leaves the previous index untouched. The previous index
+// is removed only by the next rebuild, once other processes have switched from it.
+// The record of changed components is then cleared, unless the datastore is open
+// read-only, so that the index can be rebuilt while it is in use; use ClearChanged
+// once the datastore is no longer in use.
+func (svc *Svc) Index(options ...IndexOptions) error {
+	var opts IndexOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if svc.index.inMemory {
+		return svc.indexInMemory(opts)
+	}
+	if err := svc.removePreviousIndexes(); err != nil {
+		return err
+	}
+	target := filepath.Join(filepath.Dir(svc.indexPath), fmt.Sprintf("%s.%d", filepath.Base(svc.indexPath), time.Now().UnixNano()))
+	tmp := target + ".build"
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	index, err := bleve.New(tmp, false, bleve.Options{Phonetic: opts.Phonetic})
+	if err != nil {
+		return err
+	}
+	err = svc.indexConcepts(index, opts, svc.IterateContext)
+	if cerr := index.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		return err
+	}
+	if err := svc.swapIndex(target); err != nil {
+		return err
+	}
+	if err := svc.ClearChanged(); err != nil && !storage.IsReadOnly(err) {
+		return err
+	}
+	return nil
+}
+
+// ClearChanged forgets the components changed in the datastore at the path specified, such as once
+// the search index has been rebuilt using a datastore opened read-only. The datastore is opened
+// for writing only briefly, and so must not be in use by another process.
+func ClearChanged(path string) error {
+	store, err := boltdb.New(path, false)
+	if err != nil {
+		return err
+	}
+	err = store.ClearChanged()
+	if cerr := store.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// indexInMemory builds a new in-memory search index, replacing the previous index once complete
+func (svc *Svc) indexInMemory(opts IndexOptions) error {
+	index := memory.New()
+	if err := svc.indexConcepts(index, opts, svc.IterateContext); err != nil {
+		return err
+	}
+	if err := svc.index.replace(index, ""); err != nil {
+		return err
+	}
+	return svc.ClearChanged()
+}
+
+// IndexIncremental updates the search index for only those concepts changed since the index was last
+// updated, including the descendants of concepts whose place in the hierarchy has changed. The documents
+// for the descriptions of each affected concept are deleted and then indexed again.
+func (svc *Svc) IndexIncremental(options ...IndexOptions) error {
+	var opts IndexOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	changed, err := svc.GetChanged()
+	if err != nil {
+		return err
+	}
+	conceptIDs, err := svc.changedConcepts(changed)
+	if err != nil {
+		return err
+	}
+	concepts := make([]*snomed.Concept, 0, len(conceptIDs))
+	descriptionIDs := make([]int64, 0)
+	for id := range conceptIDs {
+		concept, err := svc.GetConcept(id)
+		if storage.IsNotFound(err) {
+			continue // e.g. a reference set item for a concept not yet imported
+		}
+		if err != nil {
+			return err
+		}
+		descriptions, err := svc.GetDescriptions(concept)
+		if err != nil {
+			return err
+		}
+		for _, d := range descriptions {
+			descriptionIDs = append(descriptionIDs, d.Id)
+		}
+		concepts = append(concepts, concept)
+	}
+	if err := svc.Search.Delete(descriptionIDs); err != nil {
+		return err
+	}
+	err = svc.indexConcepts(svc.Search, opts, func(ctx context.Context, fn func(*snomed.Concept) error) error {
+		for _, concept := range concepts {
+			if err := fn(concept); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return svc.ClearChanged()
+}
+
+// changedConcepts resolves the changed components into the concepts affected, including all
+// descendants of those concepts whose place in the hierarchy has changed, as their
+// recursive parents will also have changed.
+func (svc *Svc) changedConcepts(changed map[int64]bool) (map[int64]bool, error) {
+	result := make(map[int64]bool)
+	for id, moved := range changed {
+		conceptID := id
+		if snomed.Identifier(id).IsDescription() {
+			description, err := svc.GetDescription(id)
+			if storage.IsNotFound(err) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			conceptID = description.ConceptId
+		} else if !snomed.Identifier(id).IsConcept() {
+			continue
+		}
+		result[conceptID] = true
+		if !moved {
+			continue
+		}
+		concept, err := svc.GetConcept(conceptID)
+		if storage.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		children, err := svc.GetAllChildrenIDs(concept)
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range children {
+			result[child] = true
+		}
+	}
+	return result, nil
+}
+
+// indexConcepts builds the snomed.ExtendedDescription for each description of the concepts
+// passed to the iterator function, and passes them to the search service specified for indexing.
+// Concepts are read, expanded by a pool of workers and indexed in batches, each stage running
+// concurrently. The first error from any stage cancels the others and is returned.
+func (svc *Svc) indexConcepts(index search.Search, opts IndexOptions, iterate func(context.Context, func(*snomed.Concept) error) error) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	g, ctx := errgroup.WithContext(context.Background())
+	conceptsChn := make(chan *snomed.Concept)
+	indexChn := make(chan *snomed.ExtendedDescription, 1000)
+	var processed int64
+
+	g.Go(func() error {
+		defer close(conceptsChn)
+		return iterate(ctx, func(concept *snomed.Concept) error {
+			c := *concept // the iterator may reuse the concept
+			select {
+			case conceptsChn <- &c:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
 			}
-			return nil
 		})
-		close(conceptsChn) // Close conceptsChn to signal to buildExtendedDescriptionsFromConcept goroutines to return
-		wg.Wait()          // Wait for all buildExtendedDescriptionsFromConcept goroutines to finish
-		close(indexChn)    // Close indexChan to signal to batchIndex to return
-	}()
+	})
 
-	cpu := runtime.NumCPU()
-	for i := 0; i < cpu; i++ {
-		wg.Add(1) // Add to WaitGroup for each goroutines launched
-		go buildExtendedDescriptionsFromConcept(svc, &wg, conceptsChn, indexChn)
+	var workersGroup errgroup.Group
+	for i := 0; i < workers; i++ {
+		workersGroup.Go(func() error {
+			return svc.buildExtendedDescriptions(ctx, opts, conceptsChn, indexChn, &processed)
+		})
 	}
-	batchIndex(svc, indexChn) // Start batch indexer
-	return nil
+	g.Go(func() error {
+		defer close(indexChn)
+		return workersGroup.Wait()
+	})
+
+	g.Go(func() error {
+		return batchIndex(ctx, index, opts, indexChn, &processed)
+	})
+	return g.Wait()
 }
 
 // batchIndex receives snomed.ExtendedDescription on a channel and batches them
 // before writing to the search service using Search.Index()
-func batchIndex(svc *Svc, in <-chan snomed.ExtendedDescription) {
+func batchIndex(ctx context.Context, index search.Search, opts IndexOptions, in <-chan *snomed.ExtendedDescription, processed *int64) error {
 	var (
 		count = 0
-		eds   []*snomed.ExtendedDescription
+		eds   = make([]*snomed.ExtendedDescription, 0, indexBatchSize)
+		start = time.Now()
 	)
-	start := time.Now()
+	progress := func(done bool) {
+		if opts.Progress != nil {
+			opts.Progress(IndexProgress{Concepts: int(atomic.LoadInt64(processed)), Descriptions: count, Elapsed: time.Since(start), Done: done})
+		}
+	}
 	for ed := range in {
-		copyEd := ed
-		eds = append(eds, &copyEd)
+		eds = append(eds, ed)
 		count++
-		if count%10000 == 0 {
-			if err := svc.Search.Index(eds); err != nil {
-				panic(err)
+		if len(eds) == indexBatchSize {
+			if err := index.Index(eds); err != nil {
+				return err
 			}
-			eds = []*snomed.ExtendedDescription{}
-			elapsed := time.Since(start)
-			fmt.Fprintf(os.Stderr, "\rProcessed %d descriptions in %s. Mean time per description: %s...", count, elapsed, elapsed/time.Duration(count))
+			eds = make([]*snomed.ExtendedDescription, 0, indexBatchSize)
+			progress(false)
 		}
 	}
-	if err := svc.Search.Index(eds); err != nil {
-		panic(err)
+	if err := ctx.Err(); err != nil {
+		return err // abandoned due to an error elsewhere in the pipeline
 	}
-	fmt.Fprintf(os.Stderr, "\nProcessed total: %d descriptions in %s.\n", count, time.Since(start))
+	if err := index.Index(eds); err != nil {
+		return err
+	}
+	progress(true)
+	return nil
 }
 
-// buildExtendedDescriptionsFromConcept reads from conceptsChn and builds a
-// snomed.ExtendedDescription for each description in the specified concept and
-// passes to indexChn channel
-func buildExtendedDescriptionsFromConcept(svc *Svc, wg *sync.WaitGroup, conceptsChn chan snomed.Concept, indexChn chan snomed.ExtendedDescription) {
-	defer wg.Done()
+// buildExtendedDescriptions reads concepts from conceptsChn and builds a
+// snomed.ExtendedDescription for each description of those concepts included
+// by the options specified, and passes them to the indexChn channel
+func (svc *Svc) buildExtendedDescriptions(ctx context.Context, opts IndexOptions, conceptsChn <-chan *snomed.Concept, indexChn chan<- *snomed.ExtendedDescription, processed *int64) error {
+	tags, _, _ := language.ParseAcceptLanguage("en-GB")
 	for concept := range conceptsChn {
 		var (
-			ed         snomed.ExtendedDescription
-			err        error
-			tags, _, _ = language.ParseAcceptLanguage("en-GB")
+			ed  snomed.ExtendedDescription
+			err error
 		)
-
-		ed.Concept = &concept
-		ed.PreferredDescription = svc.MustGetPreferredSynonym(&concept, tags)
-		ed.RecursiveParentIds, err = svc.GetAllParentIDs(&concept)
+		ed.Concept = concept
+		ed.RecursiveParentIds, err = svc.GetAllParentIDs(concept)
 		if err != nil {
-			panic(err)
+			return err
 		}
-		ed.DirectParentIds, err = svc.GetParentIDsOfKind(&concept, snomed.IsA)
+		ed.ConceptRefsets, err = svc.GetReferenceSets(concept.Id)
 		if err != nil {
-			panic(err)
+			return err
 		}
-		ed.ConceptRefsets, err = svc.GetReferenceSets(concept.Id)
+		atomic.AddInt64(processed, 1)
+		if !includesAny(opts.Hierarchies, concept.Id, ed.RecursiveParentIds) || !includesAny(opts.ReferenceSets, 0, ed.ConceptRefsets) {
+			continue
+		}
+		descriptions, err := svc.GetDescriptions(concept)
 		if err != nil {
-			panic(err)
+			return err
 		}
-
-		descriptions, err := svc.GetDescriptions(&concept)
+		if len(descriptions) == 0 {
+			continue
+		}
+		preferred, found, err := svc.GetPreferredSynonym(concept, tags)
 		if err != nil {
-			panic(err)
+			return err
+		}
+		if !found {
+			preferred = descriptions[0]
+		}
+		ed.PreferredDescription = preferred
+		ed.DirectParentIds, err = svc.GetParentIDsOfKind(concept, snomed.IsA)
+		if err != nil {
+			return err
 		}
 		for _, description := range descriptions {
+			if !includesLanguage(opts.Languages, description) {
+				continue
+			}
 			edCopy := ed
 			edCopy.Description = description
 			edCopy.DescriptionRefsets, err = svc.GetReferenceSets(description.Id)
 			if err != nil {
-				panic(err)
+				return err
 			}
-			indexChn <- edCopy
+			edCopy.PreferredIn, edCopy.AcceptableIn, err = svc.languageAcceptability(description.Id, edCopy.DescriptionRefsets)
+			if err != nil {
+				return err
+			}
+			select {
+			case indexChn <- &edCopy:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+// includesAny returns whether any of the identifiers, or the additional identifier specified, are
+// in the list of those to be included. An empty list includes everything.
+func includesAny(include []int64, id int64, ids []int64) bool {
+	if len(include) == 0 {
+		return true
+	}
+	for _, v := range include {
+		if v == id {
+			return true
+		}
+		for _, w := range ids {
+			if v == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// includesLanguage returns whether the description is in one of the languages specified,
+// comparing only the base language. An empty list includes every language.
+func includesLanguage(tags []language.Tag, description *snomed.Description) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	base, _ := description.LanguageTag().Base()
+	for _, tag := range tags {
+		if b, _ := tag.Base(); b == base {
+			return true
 		}
 	}
+	return false
 }
 
 /*
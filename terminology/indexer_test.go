@@ -0,0 +1,71 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package terminology_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology"
+	"github.com/wardle/go-terminology/terminology/terminologytest"
+)
+
+// TestIndexInMemory imports a tiny release, indexes it in memory and searches it
+func TestIndexInMemory(t *testing.T) {
+	svc := terminologytest.New(t, terminology.Options{InMemoryIndex: true})
+	defer svc.Close()
+	if err := svc.Index(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		request *snomed.SearchRequest
+		first   int64 // the expected first description, or zero if no results are expected
+	}{
+		{&snomed.SearchRequest{Search: "mult scl"}, 1012016},
+		{&snomed.SearchRequest{Search: "Appendectomy"}, 1022010},
+		{&snomed.SearchRequest{Search: "apendicectomy"}, 1021015},
+		{&snomed.SearchRequest{Search: "apendicectomy", Fuzzy: snomed.SearchRequest_NO_FUZZY}, 0},
+		{&snomed.SearchRequest{Search: "24700007"}, 1012016},
+		{&snomed.SearchRequest{Search: "bone", ReferenceSetIds: []int64{991411000000109}}, 1016018},
+		{&snomed.SearchRequest{Search: "disease", RecursiveParentIds: []int64{71388002}}, 0},
+		{&snomed.SearchRequest{Search: "sclerosis", ModuleIds: []int64{999000011000000103}}, 1014015},
+		{&snomed.SearchRequest{Search: "mult scl", AcceptedLanguages: "en-GB"}, 1012016},
+		{&snomed.SearchRequest{Search: "bone", Ecl: "< 404684003 AND ^ 991411000000109"}, 1016018},
+		{&snomed.SearchRequest{Search: "disease", Ecl: "< 404684003 : 363698007 = << 21483005"}, 1009019},
+		{&snomed.SearchRequest{Search: "disease", Ecl: "< 404684003 MINUS << 6118003"}, 1006014},
+		{&snomed.SearchRequest{Search: "bone", Ecl: "<< 71388002"}, 0},
+		{&snomed.SearchRequest{Search: "disease", Ecl: "<< 64572001 |Disease|"}, 1006014},
+	}
+	for _, test := range tests {
+		results, err := svc.Search.Search(test.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.first == 0 && len(results) > 0 {
+			t.Errorf("search for '%s' unexpectedly found %v", test.request.Search, results)
+		} else if test.first != 0 && (len(results) == 0 || results[0] != test.first) {
+			t.Errorf("search for '%s' expected %d first, got %v", test.request.Search, test.first, results)
+		}
+	}
+	suggestion, err := svc.Suggest(context.Background(), &snomed.SearchRequest{Search: "multiple sclerosus"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggestion != "multiple sclerosis" {
+		t.Errorf("expected suggestion 'multiple sclerosis', got '%s'", suggestion)
+	}
+}
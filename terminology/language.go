@@ -108,3 +108,39 @@ func (svc *Svc) Match(preferred []language.Tag) Language {
 	log.Printf("failed to match language %s", matchedTag)
 	return AmericanEnglish
 }
+
+// LanguageReferenceSets returns the installed language reference sets that best match the
+// requested languages, or nil if no installed language reference set is appropriate.
+func (svc *Svc) LanguageReferenceSets(preferred []language.Tag) ([]int64, error) {
+	refset := svc.Match(preferred).LanguageReferenceSetIdentifier()
+	installed, err := svc.GetAllReferenceSets()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range installed {
+		if id == refset && refset != 0 {
+			return []int64{refset}, nil
+		}
+	}
+	return nil, nil
+}
+
+// languageAcceptability returns the language reference sets, from those specified, in which
+// the description is preferred and those in which it is acceptable.
+func (svc *Svc) languageAcceptability(descriptionID int64, refsets []int64) (preferred []int64, acceptable []int64, err error) {
+	for _, refset := range refsets {
+		item, err := svc.GetFromReferenceSet(refset, descriptionID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if item == nil || !item.Active {
+			continue
+		}
+		if lrs := item.GetLanguage(); lrs.IsPreferred() {
+			preferred = append(preferred, refset)
+		} else if lrs.IsAcceptable() {
+			acceptable = append(acceptable, refset)
+		}
+	}
+	return preferred, acceptable, nil
+}
@@ -1,15 +1,27 @@
 package bleve
 
 import (
+	"context"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
-	//dbq "github.com/blevesearch/bleve/search/query"
 	blevesearch "github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis"
 	"github.com/blevesearch/bleve/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/index/store"
 	"github.com/blevesearch/bleve/index/store/goleveldb"
 	"github.com/blevesearch/bleve/index/store/moss"
 	"github.com/blevesearch/bleve/index/upsidedown"
+	"github.com/blevesearch/bleve/mapping"
+	bsearch "github.com/blevesearch/bleve/search"
+	bquery "github.com/blevesearch/bleve/search/query"
 	"github.com/wardle/go-terminology/snomed"
 	"github.com/wardle/go-terminology/terminology/search"
 )
@@ -26,15 +38,47 @@ type bleveIndexedDocument struct {
 	ConceptIsActive           bool
 	DescriptionId             string
 	DescriptionType           string
+	DescriptionIsPreferred    bool    // whether the description is the preferred term for the concept
+	WordCount                 float64 // the number of words in the term, for ranking shorter terms first
 	ModuleId                  string
 	ConceptRefsetIds          []string
 	DescriptionRefsetIds      []string
+	PreferredInRefsetIds      []string // language reference sets in which the description is preferred
+	AcceptableInRefsetIds     []string // language reference sets in which the description is acceptable
+	docType                   string   // the document type, determining the analyser used for the description; not indexed
 }
 
+// Type returns the type of the document, so that each description is analysed according to its language
+func (doc bleveIndexedDocument) Type() string {
+	return doc.docType
+}
+
+var _ search.Search = (*bleveService)(nil)
+
 // bleveService is a search service for SNOMED-CT that implements the search.Search interface
 type bleveService struct {
-	index blevesearch.Index
-	_     search.Search
+	index           blevesearch.Index
+	readOnly        bool
+	expansions      expansions                    // alternative phrases for tokens in a search, such as abbreviations
+	languages       map[string]bool               // languages with specific analysers in the index
+	phonetic        bool                          // whether the index includes a phonetic encoding of each term
+	languageRefsets bool                          // whether the index records the language reference sets of each description
+	ranking         *snomed.SearchRequest_Ranking // weights for ranking results, unless overridden by a request
+
+	mu       sync.Mutex
+	topLevel map[int64]bool // cached identifiers of the top-level concepts, for hierarchy facets
+	dict     *dictionary    // cached dictionary of indexed words, for spelling suggestions
+}
+
+// maximumFacetTerms is the maximum number of distinct terms counted for a facet; hierarchy and
+// reference set facets are filtered after counting and so need to consider every term.
+const maximumFacetTerms = 100000
+
+// facetFields maps each facet to the indexed field that is counted
+var facetFields = map[snomed.SearchRequest_Facet]string{
+	snomed.SearchRequest_HIERARCHY:     "RecursiveParentConceptIds",
+	snomed.SearchRequest_MODULE:        "ModuleId",
+	snomed.SearchRequest_REFERENCE_SET: "ConceptRefsetIds",
 }
 
 // []byte for int64 to binary conversion
@@ -56,50 +100,45 @@ func bstoi(b string) int64 {
 	return x
 }
 
-func New(path string, readOnly bool) (search.Search, error) {
+// Options is a struct used as an argument to New() to configure the search service
+type Options struct {
+	Expansions string                        // location of a query expansion dictionary, optional
+	Phonetic   bool                          // whether a new index includes a phonetic encoding of each term, for phonetic searches
+	Ranking    *snomed.SearchRequest_Ranking // weights for ranking results, with defaults used for any not specified
+}
+
+// New opens the index at the specified location, creating a new index if none exists and not read-only
+func New(path string, readOnly bool, options ...Options) (search.Search, error) {
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	exps, err := loadExpansions(opts.Expansions)
+	if err != nil {
+		return nil, err
+	}
 	var index blevesearch.Index
-	var err error
-	if !readOnly {
-
-		textMapping := blevesearch.NewTextFieldMapping()
-		textMapping.IncludeInAll = false
-		textMapping.Store = false
-
-		boolMapping := blevesearch.NewBooleanFieldMapping()
-		boolMapping.IncludeInAll = false
-		boolMapping.Store = false
-
-		storedIDMapping := blevesearch.NewTextFieldMapping()
-		storedIDMapping.IncludeInAll = false
-		storedIDMapping.IncludeTermVectors = false
-		storedIDMapping.Store = true
-		storedIDMapping.Analyzer = keyword.Name
-
-		idMapping := blevesearch.NewTextFieldMapping()
-		idMapping.IncludeInAll = false
-		idMapping.IncludeTermVectors = false
-		idMapping.Store = false
-		idMapping.Analyzer = keyword.Name
-
-		documentMapping := blevesearch.NewDocumentMapping()
-		documentMapping.AddFieldMappingsAt("Term", textMapping)
-		documentMapping.AddFieldMappingsAt("PreferredTerm", textMapping)
-		documentMapping.AddFieldMappingsAt("ConceptId", storedIDMapping)
-		documentMapping.AddFieldMappingsAt("RecursiveParentConceptIds", idMapping)
-		documentMapping.AddFieldMappingsAt("DirectParentConceptIds", idMapping)
-		documentMapping.AddFieldMappingsAt("Language", idMapping)
-		documentMapping.AddFieldMappingsAt("DescriptionIsActive", boolMapping)
-		documentMapping.AddFieldMappingsAt("ConceptIsActive", boolMapping)
-		documentMapping.AddFieldMappingsAt("DescriptionId", idMapping)
-		documentMapping.AddFieldMappingsAt("DescriptionType", idMapping)
-		documentMapping.AddFieldMappingsAt("ModuleId", idMapping)
-		documentMapping.AddFieldMappingsAt("ConceptRefsetIds", idMapping)
-		documentMapping.AddFieldMappingsAt("DescriptionRefsetIds", idMapping)
-
-		mapping := blevesearch.NewIndexMapping()
-		mapping.StoreDynamic = false
-		mapping.DefaultType = "bleveIndexedDocument"
-		mapping.AddDocumentMapping("bleveIndexedDocument", documentMapping)
+	if _, statErr := os.Stat(path); readOnly || statErr == nil {
+		index, err = blevesearch.OpenUsing(path, map[string]interface{}{
+			"read_only": readOnly,
+		})
+	} else {
+
+		indexMapping := blevesearch.NewIndexMapping()
+		if err := addAnalyzers(indexMapping); err != nil {
+			return nil, err
+		}
+		if opts.Phonetic {
+			if err := addPhoneticAnalyzer(indexMapping); err != nil {
+				return nil, err
+			}
+		}
+		indexMapping.StoreDynamic = false
+		indexMapping.DefaultType = defaultDocumentType
+		indexMapping.AddDocumentMapping(defaultDocumentType, newDocumentMapping(defaultAnalyzer, opts.Phonetic))
+		for lang := range languageFilters {
+			indexMapping.AddDocumentMapping(languageDocumentType(lang), newDocumentMapping(languageAnalyzer(lang), opts.Phonetic))
+		}
 
 		/*
 			//bolt index (default) - space ineficient, slow indexing
@@ -108,21 +147,110 @@ func New(path string, readOnly bool) (search.Search, error) {
 
 		/*
 			//goleveldb index - space efficient as slow as bolt indexing TODO: Optimise compaction with options
-			index, err = blevesearch.NewUsing(path, mapping, upsidedown.Name, goleveldb.Name, map[string]interface{}{})
+			index, err = blevesearch.NewUsing(path, indexMapping, upsidedown.Name, goleveldb.Name, map[string]interface{}{})
 		*/
 
 		//moss index - with goleveldb storage, fast indexing & space efficient
-		kvconfig := map[string]interface{}{
-			"mossLowerLevelStoreName": goleveldb.Name,
-		}
-		index, err = blevesearch.NewUsing(path, mapping, upsidedown.Name, moss.Name, kvconfig)
+		index, err = blevesearch.NewUsing(path, indexMapping, upsidedown.Name, moss.Name, kvconfig())
+	}
+	if err != nil {
+		return nil, err
+	}
+	languageRefsets := hasField(index.Mapping(), "PreferredInRefsetIds")
+	if !languageRefsets {
+		log.Printf("search index at %s does not record language reference sets, so searches will not be filtered by language; rebuild the index to enable", path)
+	}
+	return &bleveService{
+		index:           index,
+		readOnly:        readOnly,
+		expansions:      exps,
+		languages:       mappedLanguages(index.Mapping()),
+		phonetic:        hasTermField(index.Mapping(), phoneticField),
+		languageRefsets: languageRefsets,
+		ranking:         opts.Ranking,
+	}, nil
+}
 
-	} else {
-		index, err = blevesearch.OpenUsing(path, map[string]interface{}{
-			"read_only": readOnly,
-		})
+// kvconfig returns the configuration of the key-value store for a new index
+func kvconfig() map[string]interface{} {
+	return map[string]interface{}{
+		"mossLowerLevelStoreName": goleveldb.Name,
+	}
+}
+
+// newDocumentMapping returns the mapping for a document with terms analysed by the analyser specified,
+// optionally including a phonetic encoding of each term
+func newDocumentMapping(analyzer string, phonetic bool) *mapping.DocumentMapping {
+	textMapping := blevesearch.NewTextFieldMapping()
+	textMapping.IncludeInAll = false
+	textMapping.Store = false
+	textMapping.Analyzer = analyzer
+
+	// the matched term is stored, with term vectors, to permit highlighting
+	termMapping := blevesearch.NewTextFieldMapping()
+	termMapping.IncludeInAll = false
+	termMapping.IncludeTermVectors = true
+	termMapping.Store = true
+	termMapping.Analyzer = analyzer
+
+	// the words of each term are also indexed without stemming, to build a dictionary for spelling suggestions
+	spellingMapping := blevesearch.NewTextFieldMapping()
+	spellingMapping.Name = spellingField
+	spellingMapping.IncludeInAll = false
+	spellingMapping.Store = false
+	spellingMapping.Analyzer = spellingAnalyzer
+	termFields := []*mapping.FieldMapping{termMapping, spellingMapping}
+
+	// optionally, the words of each term are also indexed by their phonetic encodings
+	if phonetic {
+		phoneticMapping := blevesearch.NewTextFieldMapping()
+		phoneticMapping.Name = phoneticField
+		phoneticMapping.IncludeInAll = false
+		phoneticMapping.Store = false
+		phoneticMapping.Analyzer = phoneticAnalyzer
+		termFields = append(termFields, phoneticMapping)
 	}
-	return &bleveService{index: index}, err
+
+	numericMapping := blevesearch.NewNumericFieldMapping()
+	numericMapping.IncludeInAll = false
+	numericMapping.Store = false
+
+	boolMapping := blevesearch.NewBooleanFieldMapping()
+	boolMapping.IncludeInAll = false
+	boolMapping.Store = false
+
+	storedIDMapping := blevesearch.NewTextFieldMapping()
+	storedIDMapping.IncludeInAll = false
+	storedIDMapping.IncludeTermVectors = false
+	storedIDMapping.Store = true
+	storedIDMapping.Analyzer = keyword.Name
+
+	idMapping := blevesearch.NewTextFieldMapping()
+	idMapping.IncludeInAll = false
+	idMapping.IncludeTermVectors = false
+	idMapping.Store = false
+	idMapping.Analyzer = keyword.Name
+
+	documentMapping := blevesearch.NewDocumentMapping()
+	documentMapping.AddFieldMappingsAt("Term", termFields...)
+	documentMapping.AddFieldMappingsAt("PreferredTerm", textMapping)
+	documentMapping.AddFieldMappingsAt("ConceptId", storedIDMapping)
+	documentMapping.AddFieldMappingsAt("RecursiveParentConceptIds", idMapping)
+	documentMapping.AddFieldMappingsAt("DirectParentConceptIds", idMapping)
+	documentMapping.AddFieldMappingsAt("Language", idMapping)
+	documentMapping.AddFieldMappingsAt("DescriptionIsActive", boolMapping)
+	documentMapping.AddFieldMappingsAt("ConceptIsActive", boolMapping)
+	documentMapping.AddFieldMappingsAt("DescriptionIsPreferred", boolMapping)
+	documentMapping.AddFieldMappingsAt("WordCount", numericMapping)
+	documentMapping.AddFieldMappingsAt("DescriptionId", idMapping)
+	documentMapping.AddFieldMappingsAt("DescriptionType", idMapping)
+	documentMapping.AddFieldMappingsAt("ModuleId", idMapping)
+	documentMapping.AddFieldMappingsAt("ConceptRefsetIds", idMapping)
+	documentMapping.AddFieldMappingsAt("DescriptionRefsetIds", idMapping)
+	documentMapping.AddFieldMappingsAt("PreferredInRefsetIds", idMapping)
+	documentMapping.AddFieldMappingsAt("AcceptableInRefsetIds", idMapping)
+
+	return documentMapping
 }
 
 func (bs *bleveService) Index(eds []*snomed.ExtendedDescription) error {
@@ -136,10 +264,13 @@ func (bs *bleveService) Index(eds []*snomed.ExtendedDescription) error {
 		doc.PreferredTerm = ed.PreferredDescription.Term
 		doc.ConceptId = itobs(ed.Concept.Id)
 		doc.Language = ed.Description.LanguageCode
+		doc.docType = bs.documentType(ed.Description.LanguageCode)
 		doc.DescriptionIsActive = ed.Description.Active
 		doc.ConceptIsActive = ed.Concept.Active
 		doc.DescriptionId = itobs(ed.Description.Id)
 		doc.DescriptionType = itobs(ed.Description.TypeId)
+		doc.DescriptionIsPreferred = ed.Description.Id == ed.PreferredDescription.Id
+		doc.WordCount = float64(len(strings.Fields(ed.Description.Term)))
 		doc.ModuleId = itobs(ed.Description.ModuleId)
 
 		for _, v := range ed.RecursiveParentIds {
@@ -154,6 +285,12 @@ func (bs *bleveService) Index(eds []*snomed.ExtendedDescription) error {
 		for _, v := range ed.DescriptionRefsets {
 			doc.DescriptionRefsetIds = append(doc.DescriptionRefsetIds, itobs(v))
 		}
+		for _, v := range ed.PreferredIn {
+			doc.PreferredInRefsetIds = append(doc.PreferredInRefsetIds, itobs(v))
+		}
+		for _, v := range ed.AcceptableIn {
+			doc.AcceptableInRefsetIds = append(doc.AcceptableInRefsetIds, itobs(v))
+		}
 
 		err := batch.Index(doc.DescriptionId, doc)
 		//fmt.Printf("%+v\n", doc)
@@ -163,10 +300,47 @@ func (bs *bleveService) Index(eds []*snomed.ExtendedDescription) error {
 	}
 
 	err := bs.index.Batch(batch)
+	bs.mu.Lock()
+	bs.topLevel, bs.dict = nil, nil
+	bs.mu.Unlock()
+	return err
+}
+
+// Delete removes the documents for the specified descriptions from the index
+func (bs *bleveService) Delete(descriptionIDs []int64) error {
+	batch := bs.index.NewBatch()
+	for _, id := range descriptionIDs {
+		batch.Delete(itobs(id))
+	}
+	err := bs.index.Batch(batch)
+	bs.mu.Lock()
+	bs.topLevel, bs.dict = nil, nil
+	bs.mu.Unlock()
 	return err
 }
 
+// Search executes a search request and returns description identifiers
 func (bs *bleveService) Search(search *snomed.SearchRequest) ([]int64, error) {
+	return bs.SearchContext(context.Background(), search)
+}
+
+// SearchContext executes a search request and returns description identifiers,
+// abandoning the search if the context is cancelled or its deadline passes.
+func (bs *bleveService) SearchContext(ctx context.Context, search *snomed.SearchRequest) ([]int64, error) {
+	result, err := bs.SearchHits(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(result.Hits))
+	for i, hit := range result.Hits {
+		results[i] = hit.DescriptionID
+	}
+	return results, nil
+}
+
+// SearchHits executes a search request and returns a page of scored hits, starting at
+// the position given by the request cursor or, if there is no cursor, the request offset.
+func (bs *bleveService) SearchHits(ctx context.Context, request *snomed.SearchRequest) (*search.Result, error) {
 	/*
 		// SearchRequest permits an arbitrary free-text search of the hierarchy.
 		message SearchRequest {
@@ -187,25 +361,49 @@ func (bs *bleveService) Search(search *snomed.SearchRequest) ([]int64, error) {
 		}
 	*/
 
-	if search.Search == "" {
-		return []int64{}, fmt.Errorf("No search string in request")
+	if request.Search == "" {
+		return nil, fmt.Errorf("No search string in request")
+	}
+
+	offset := int(request.Offset)
+	if request.Cursor != "" {
+		var err error
+		if offset, request.Fuzzy, err = search.DecodeCursor(request.Cursor); err != nil {
+			return nil, err
+		}
+		request.Offset, request.Cursor = int32(offset), ""
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", offset)
+	}
+
+	if len(request.RecursiveParentIds) == 0 {
+		request.RecursiveParentIds = []int64{138875005}
 	}
 
-	if len(search.RecursiveParentIds) == 0 {
-		search.RecursiveParentIds = []int64{138875005}
+	// an index built before descriptions recorded their language reference sets cannot be filtered by them
+	if !bs.languageRefsets {
+		request.LanguageReferenceSetIds = nil
 	}
 
-	if search.MaximumHits == 0 {
-		search.MaximumHits = 200
+	if request.MaximumHits == 0 {
+		request.MaximumHits = 200
 	}
 
-	mapping := bs.index.Mapping()
-	analyzer := mapping.AnalyzerNamed(mapping.AnalyzerNameForPath("Term"))
-	tokens := analyzer.Analyze([]byte(search.Search))
+	analyzer := bs.queryAnalyzer(request.AcceptedLanguages)
+	tokens := analyzer.Analyze([]byte(request.Search))
+	var phonetic map[int][]bquery.Query
+	if request.Phonetic && bs.phonetic {
+		phonetic = bs.phoneticQueries(request.Search)
+	}
+	words := searchWords(request.Search)
 	booleanQuery := blevesearch.NewBooleanQuery()
+	tokenStrings := make([]string, 0, len(tokens))
 	for _, token := range tokens {
 		tokenString := string(token.Term)
+		tokenStrings = append(tokenStrings, tokenString)
 
+		var tokenQuery bquery.Query
 		termQuery := blevesearch.NewTermQuery(tokenString)
 		termQuery.SetField("Term")
 
@@ -213,88 +411,508 @@ func (bs *bleveService) Search(search *snomed.SearchRequest) ([]int64, error) {
 			prefixQuery := blevesearch.NewPrefixQuery(tokenString)
 			prefixQuery.SetField("Term")
 
-			if search.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY {
+			if request.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY {
 				fuzzyQuery := blevesearch.NewFuzzyQuery(tokenString)
 				fuzzyQuery.SetField("Term")
 				fuzzyQuery.SetFuzziness(2)
 				prefixBooleanQuery := blevesearch.NewBooleanQuery()
 				prefixBooleanQuery.AddShould(prefixQuery)
 				prefixBooleanQuery.AddShould(fuzzyQuery)
-				booleanQuery.AddMust(prefixBooleanQuery)
+				tokenQuery = prefixBooleanQuery
 			} else {
-				booleanQuery.AddMust(prefixQuery)
+				tokenQuery = prefixQuery
 			}
 		} else {
-			booleanQuery.AddMust(termQuery)
+			tokenQuery = termQuery
+		}
+
+		// expansions and phonetic matches are alternatives to the token, weighted below the token itself
+		alternatives := bs.expansionQueries(analyzer, words[token.Position])
+		alternatives = append(alternatives, phonetic[token.Position]...)
+		if len(alternatives) > 0 {
+			alternativesBooleanQuery := blevesearch.NewBooleanQuery()
+			alternativesBooleanQuery.AddShould(tokenQuery)
+			alternativesBooleanQuery.AddShould(alternatives...)
+			tokenQuery = alternativesBooleanQuery
 		}
+		booleanQuery.AddMust(tokenQuery)
 	}
 
-	//Exclude FSN (no current option to disable in snomed.SearchRequest)
-	excludeFSNQuery := blevesearch.NewTermQuery("900000000000003001")
-	excludeFSNQuery.SetField("DescriptionType")
-	booleanQuery.AddMustNot(excludeFSNQuery)
+	if len(tokenStrings) > 0 {
+		addRanking(booleanQuery, rankingFor(bs.ranking, request.Ranking), tokenStrings)
+	}
+	addExclusions(booleanQuery, request)
+	filters := filterQueries(request)
+
+	// a search for a valid identifier finds the identified component first, ahead of the text results
+	identified, err := bs.identifierHit(ctx, request, filters)
+	if err != nil {
+		return nil, err
+	}
+	textOffset, textHits := offset, int(request.MaximumHits)
+	if identified != nil {
+		// the identified description is not also returned as a text match
+		identifiedQuery := blevesearch.NewTermQuery(itobs(identified.DescriptionID))
+		identifiedQuery.SetField("DescriptionId")
+		booleanQuery.AddMustNot(identifiedQuery)
+		if offset == 0 {
+			textHits--
+		} else {
+			textOffset--
+		}
+	}
 
 	query := blevesearch.NewConjunctionQuery(booleanQuery)
+	query.AddQuery(filters...)
+
+	searchRequest := blevesearch.NewSearchRequest(query)
+	searchRequest.Size = textHits
+	searchRequest.From = textOffset
+	searchRequest.Explain = request.Explain
+	if request.CollapseConcepts {
+		// concepts are collapsed from the first hit, fetching enough descriptions for several per concept
+		searchRequest.Size = collapsePageSize(textOffset, textHits)
+		searchRequest.From = 0
+	}
+	searchRequest.Fields = []string{"ConceptId"}
+	if request.Highlight {
+		searchRequest.Highlight = blevesearch.NewHighlight()
+		searchRequest.Highlight.AddField("Term")
+	}
+	for _, facet := range request.Facets {
+		field, ok := facetFields[facet]
+		if !ok {
+			return nil, fmt.Errorf("unsupported facet: %v", facet)
+		}
+		searchRequest.AddFacet(facet.String(), blevesearch.NewFacetRequest(field, maximumFacetTerms))
+	}
+
+	searchResults, err := bs.index.SearchInContext(ctx, searchRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if (searchResults.Total == 0) && (identified == nil) && (request.Fuzzy != snomed.SearchRequest_ALWAYS_FUZZY) && (request.Fuzzy != snomed.SearchRequest_NO_FUZZY) {
+		request.Fuzzy = snomed.SearchRequest_ALWAYS_FUZZY
+		return bs.SearchHits(ctx, request)
+	}
 
-	for _, refset := range search.ReferenceSetIds {
+	result := &search.Result{Total: searchResults.Total, Fuzzy: request.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY}
+	if request.Explain {
+		dump, err := bquery.DumpQuery(bs.index.Mapping(), query)
+		if err != nil {
+			return nil, err
+		}
+		result.Explanation = &search.Explanation{Tokens: tokenStrings, Query: dump}
+	}
+	more := false
+	if request.CollapseConcepts {
+		seen := make(map[int64]bool)
+		if identified != nil {
+			seen[identified.ConceptID] = true
+		}
+		if result.Hits, more, err = bs.collapseConcepts(ctx, searchRequest, searchResults, seen, textOffset, textHits); err != nil {
+			return nil, err
+		}
+	} else {
+		for _, hit := range searchResults.Hits {
+			result.Hits = append(result.Hits, newHit(hit))
+		}
+		more = uint64(textOffset+len(result.Hits)) < searchResults.Total
+	}
+	if identified != nil {
+		result.Total++
+		if offset == 0 {
+			result.Hits = append([]search.Hit{*identified}, result.Hits...)
+		}
+	}
+	if len(result.Hits) > 0 && more {
+		result.NextCursor = search.EncodeCursor(offset+len(result.Hits), request.Fuzzy)
+	}
+	for _, facet := range request.Facets {
+		var include map[int64]bool // nil to include all terms
+		switch facet {
+		case snomed.SearchRequest_HIERARCHY:
+			if include, err = bs.topLevelConcepts(ctx); err != nil {
+				return nil, err
+			}
+		case snomed.SearchRequest_REFERENCE_SET:
+			include = make(map[int64]bool)
+			for _, id := range request.FacetReferenceSetIds {
+				include[id] = true
+			}
+		}
+		result.Facets = append(result.Facets, facetCounts(facet, searchResults.Facets[facet.String()], include))
+	}
+	return result, nil
+}
+
+// addExclusions excludes the types of description not requested, and concepts that are members
+// of excluded reference sets, from the boolean query
+func addExclusions(booleanQuery *bquery.BooleanQuery, request *snomed.SearchRequest) {
+	for _, excluded := range search.ExcludedDescriptionTypes(request) {
+		excludeTypeQuery := blevesearch.NewTermQuery(itobs(int64(excluded)))
+		excludeTypeQuery.SetField("DescriptionType")
+		booleanQuery.AddMustNot(excludeTypeQuery)
+	}
+	for _, refset := range request.ExcludeReferenceSetIds {
+		excludeRefsetQuery := blevesearch.NewTermQuery(itobs(refset))
+		excludeRefsetQuery.SetField("ConceptRefsetIds")
+		booleanQuery.AddMustNot(excludeRefsetQuery)
+	}
+}
+
+// filterQueries returns the queries restricting a search by status, hierarchy, reference set, module and language
+func filterQueries(request *snomed.SearchRequest) []bquery.Query {
+	var result []bquery.Query
+
+	for _, refset := range request.ReferenceSetIds {
 		refsetQuery := blevesearch.NewTermQuery(itobs(refset))
 		refsetQuery.SetField("ConceptRefsetIds")
-		query.AddQuery(refsetQuery)
+		result = append(result, refsetQuery)
+	}
+
+	for _, refset := range request.DescriptionReferenceSetIds {
+		refsetQuery := blevesearch.NewTermQuery(itobs(refset))
+		refsetQuery.SetField("DescriptionRefsetIds")
+		result = append(result, refsetQuery)
+	}
+
+	if len(request.ModuleIds) > 0 {
+		moduleDisjunctionQuery := blevesearch.NewDisjunctionQuery()
+		for _, module := range request.ModuleIds {
+			moduleQuery := blevesearch.NewTermQuery(itobs(module))
+			moduleQuery.SetField("ModuleId")
+			moduleDisjunctionQuery.AddQuery(moduleQuery)
+		}
+		result = append(result, moduleDisjunctionQuery)
 	}
 
-	if !search.IncludeInactive {
+	if !request.IncludeInactive {
 		isActiveQuery := blevesearch.NewTermQuery("T")
 		isActiveQuery.SetField("ConceptIsActive")
-		query.AddQuery(isActiveQuery)
+		result = append(result, isActiveQuery)
 	}
 
-	if len(search.RecursiveParentIds) > 0 {
+	if len(request.RecursiveParentIds) > 0 {
 		recursiveDisjunctionQuery := blevesearch.NewDisjunctionQuery()
-		for _, recursiveParent := range search.RecursiveParentIds {
+		for _, recursiveParent := range request.RecursiveParentIds {
 			recursiveParentQuery := blevesearch.NewTermQuery(itobs(recursiveParent))
 			recursiveParentQuery.SetField("RecursiveParentConceptIds")
 			recursiveDisjunctionQuery.AddQuery(recursiveParentQuery)
+			if request.IncludeRecursiveParents {
+				conceptQuery := blevesearch.NewTermQuery(itobs(recursiveParent))
+				conceptQuery.SetField("ConceptId")
+				recursiveDisjunctionQuery.AddQuery(conceptQuery)
+			}
+		}
+		result = append(result, recursiveDisjunctionQuery)
+	}
+
+	if len(request.LanguageReferenceSetIds) > 0 {
+		languageDisjunctionQuery := blevesearch.NewDisjunctionQuery()
+		for _, refset := range request.LanguageReferenceSetIds {
+			preferredQuery := blevesearch.NewTermQuery(itobs(refset))
+			preferredQuery.SetField("PreferredInRefsetIds")
+			acceptableQuery := blevesearch.NewTermQuery(itobs(refset))
+			acceptableQuery.SetField("AcceptableInRefsetIds")
+			languageDisjunctionQuery.AddQuery(preferredQuery, acceptableQuery)
+		}
+		result = append(result, languageDisjunctionQuery)
+	}
+
+	if len(request.ConceptIds) > 0 {
+		conceptDisjunctionQuery := blevesearch.NewDisjunctionQuery()
+		for _, concept := range request.ConceptIds {
+			conceptQuery := blevesearch.NewTermQuery(itobs(concept))
+			conceptQuery.SetField("ConceptId")
+			conceptDisjunctionQuery.AddQuery(conceptQuery)
 		}
-		query.AddQuery(recursiveDisjunctionQuery)
+		result = append(result, conceptDisjunctionQuery)
 	}
 
-	if len(search.DirectParentIds) > 0 {
+	if len(request.DirectParentIds) > 0 {
 		directDisjunctionQuery := blevesearch.NewDisjunctionQuery()
-		for _, directParent := range search.DirectParentIds {
+		for _, directParent := range request.DirectParentIds {
 			directParentQuery := blevesearch.NewTermQuery(itobs(directParent))
 			directParentQuery.SetField("DirectParentConceptIds")
 			directDisjunctionQuery.AddQuery(directParentQuery)
 		}
-		query.AddQuery(directDisjunctionQuery)
+		result = append(result, directDisjunctionQuery)
 	}
+	return result
+}
 
-	//dump, _ := dbq.DumpQuery(bs.index.Mapping(), query)
-	//print(dump)
-
+// identifierHit returns the component identified by the search string, if the search is for a valid
+// identifier and the component matches the filters specified. The preferred term is returned for a
+// concept identifier, or the description itself for a description identifier.
+func (bs *bleveService) identifierHit(ctx context.Context, request *snomed.SearchRequest, filters []bquery.Query) (*search.Hit, error) {
+	s := strings.TrimSpace(request.Search)
+	if len(s) < search.MinimumIdentifierLength || len(s) > search.MaximumIdentifierLength {
+		return nil, nil
+	}
+	id, err := snomed.ParseValidIdentifier(s, true)
+	if err != nil {
+		return nil, nil
+	}
+	identifierQuery := blevesearch.NewBooleanQuery()
+	switch {
+	case id.IsConcept():
+		conceptQuery := blevesearch.NewTermQuery(itobs(id.Integer()))
+		conceptQuery.SetField("ConceptId")
+		preferredQuery := blevesearch.NewTermQuery("T")
+		preferredQuery.SetField("DescriptionIsPreferred")
+		identifierQuery.AddMust(conceptQuery)
+		identifierQuery.AddShould(preferredQuery)
+	case id.IsDescription():
+		descriptionQuery := blevesearch.NewTermQuery(itobs(id.Integer()))
+		descriptionQuery.SetField("DescriptionId")
+		identifierQuery.AddMust(descriptionQuery)
+	default:
+		return nil, nil
+	}
+	addExclusions(identifierQuery, request)
+	query := blevesearch.NewConjunctionQuery(identifierQuery)
+	query.AddQuery(filters...)
 	searchRequest := blevesearch.NewSearchRequest(query)
-	searchRequest.Size = int(search.MaximumHits)
+	searchRequest.Size = 1
 	searchRequest.Fields = []string{"ConceptId"}
+	searchRequest.Explain = request.Explain
+	searchResults, err := bs.index.SearchInContext(ctx, searchRequest)
+	if err != nil || len(searchResults.Hits) == 0 {
+		return nil, err
+	}
+	hit := newHit(searchResults.Hits[0])
+	return &hit, nil
+}
+
+// newHit converts a bleve search hit into a search result
+func newHit(hit *bsearch.DocumentMatch) search.Hit {
+	h := search.Hit{DescriptionID: bstoi(hit.ID), Score: hit.Score}
+	if conceptID, ok := hit.Fields["ConceptId"].(string); ok {
+		h.ConceptID = bstoi(conceptID)
+	}
+	if fragments := hit.Fragments["Term"]; len(fragments) > 0 {
+		h.Highlight = fragments[0]
+	}
+	if hit.Expl != nil {
+		h.Explanation = hit.Expl.String()
+	}
+	return h
+}
+
+// collapsePageSize returns the number of descriptions to fetch at a time when collapsing hits by concept
+func collapsePageSize(offset int, maximumHits int) int {
+	return 4 * (offset + maximumHits + 1)
+}
+
+// collapseConcepts returns the best-scoring hit for each distinct concept not already seen, skipping
+// the number of concepts specified by offset and returning at most maximumHits, together with whether
+// there are more concepts to follow. Hits are taken from the results given, which must start from the
+// first hit, and from further pages of the search request as necessary.
+func (bs *bleveService) collapseConcepts(ctx context.Context, searchRequest *blevesearch.SearchRequest, searchResults *blevesearch.SearchResult, seen map[int64]bool, offset int, maximumHits int) ([]search.Hit, bool, error) {
+	var hits []search.Hit
+	distinct := 0
+	searchRequest.Facets = nil // facets are counted only from the first page
+	for {
+		for _, hit := range searchResults.Hits {
+			h := newHit(hit)
+			if seen[h.ConceptID] {
+				continue
+			}
+			seen[h.ConceptID] = true
+			if distinct++; distinct <= offset {
+				continue
+			}
+			if len(hits) == maximumHits {
+				return hits, true, nil
+			}
+			hits = append(hits, h)
+		}
+		searchRequest.From += len(searchResults.Hits)
+		if len(searchResults.Hits) == 0 || uint64(searchRequest.From) >= searchResults.Total {
+			return hits, false, nil
+		}
+		var err error
+		if searchResults, err = bs.index.SearchInContext(ctx, searchRequest); err != nil {
+			return nil, false, err
+		}
+	}
+}
 
-	searchResults, err := bs.index.Search(searchRequest)
+// expansionQueries returns queries matching each of the configured expansions for the word, weighted
+// below a match on the word itself. Each expansion is analysed in the same way as the search string
+// and all of its tokens must match.
+func (bs *bleveService) expansionQueries(analyzer *analysis.Analyzer, word string) []bquery.Query {
+	var result []bquery.Query
+	for _, expansion := range bs.expansions[word] {
+		tokens := analyzer.Analyze([]byte(expansion))
+		if len(tokens) == 0 {
+			continue
+		}
+		expansionQuery := blevesearch.NewConjunctionQuery()
+		for _, t := range tokens {
+			termQuery := blevesearch.NewTermQuery(string(t.Term))
+			termQuery.SetField("Term")
+			expansionQuery.AddQuery(termQuery)
+		}
+		expansionQuery.SetBoost(expansionBoost)
+		result = append(result, expansionQuery)
+	}
+	return result
+}
+
+// facetCounts converts the bleve facet result into counts ordered by descending count,
+// optionally limited to those concepts specified.
+func facetCounts(facet snomed.SearchRequest_Facet, fr *bsearch.FacetResult, include map[int64]bool) search.Facet {
+	result := search.Facet{Facet: facet}
+	if fr == nil {
+		return result
+	}
+	for _, term := range fr.Terms {
+		id := bstoi(term.Term)
+		if include == nil || include[id] {
+			result.Counts = append(result.Counts, search.FacetCount{ConceptID: id, Count: term.Count})
+		}
+	}
+	sort.SliceStable(result.Counts, func(i, j int) bool {
+		return result.Counts[i].Count > result.Counts[j].Count
+	})
+	return result
+}
+
+// topLevelConcepts returns the identifiers of the active concepts that are direct children
+// of the root concept, as found in the index. The result is cached until the index changes.
+func (bs *bleveService) topLevelConcepts(ctx context.Context) (map[int64]bool, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if bs.topLevel != nil {
+		return bs.topLevel, nil
+	}
+	parentQuery := blevesearch.NewTermQuery(itobs(int64(snomed.Root)))
+	parentQuery.SetField("DirectParentConceptIds")
+	isActiveQuery := blevesearch.NewTermQuery("T")
+	isActiveQuery.SetField("ConceptIsActive")
+	searchRequest := blevesearch.NewSearchRequest(blevesearch.NewConjunctionQuery(parentQuery, isActiveQuery))
+	searchRequest.Size = 0
+	searchRequest.AddFacet("ConceptId", blevesearch.NewFacetRequest("ConceptId", maximumFacetTerms))
+	searchResults, err := bs.index.SearchInContext(ctx, searchRequest)
 	if err != nil {
 		return nil, err
 	}
+	topLevel := make(map[int64]bool)
+	if fr := searchResults.Facets["ConceptId"]; fr != nil {
+		for _, term := range fr.Terms {
+			topLevel[bstoi(term.Term)] = true
+		}
+	}
+	bs.topLevel = topLevel
+	return topLevel, nil
+}
 
-	var results []int64
-	for _, hit := range searchResults.Hits {
-		//conceptID, _ := strconv.ParseInt(hit.Fields["ConceptId"].(string), 10, 64)
-		descriptionID := bstoi(hit.ID)
-		results = append(results, descriptionID)
+// Close flushes any outstanding changes to disk and closes the index
+func (bs *bleveService) Close() error {
+	var err error
+	if !bs.readOnly {
+		err = bs.flush(flushTimeout)
+	}
+	if closeErr := bs.index.Close(); err == nil {
+		err = closeErr
 	}
+	return err
+}
+
+// snapshotBatchSize is the number of key-value pairs copied in each batch when taking a snapshot
+const snapshotBatchSize = 10000
 
-	if (len(results) == 0) && (search.Fuzzy != snomed.SearchRequest_ALWAYS_FUZZY) && (search.Fuzzy != snomed.SearchRequest_NO_FUZZY) {
-		search.Fuzzy = snomed.SearchRequest_ALWAYS_FUZZY
-		return bs.Search(search)
+// Snapshot writes a consistent copy of the index, as it was when called, to a new index at the
+// location specified, without waiting for searches or indexing in progress. The files of a live
+// index cannot be copied directly, as moss persists changes asynchronously.
+func (bs *bleveService) Snapshot(path string) error {
+	_, source, err := bs.index.Advanced()
+	if err != nil {
+		return err
 	}
-	//fmt.Printf("%+v\n", results)
-	return results, nil
+	reader, err := source.Reader()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	index, err := blevesearch.NewUsing(path, bs.index.Mapping(), upsidedown.Name, moss.Name, kvconfig())
+	if err != nil {
+		return err
+	}
+	snapshot := &bleveService{index: index}
+	if err := snapshot.copyFrom(reader); err != nil {
+		index.Close()
+		return err
+	}
+	return snapshot.Close()
 }
 
-func (bs *bleveService) Close() error {
-	return bs.index.Close()
+// copyFrom copies every key-value pair from the reader into the store underlying the index
+func (bs *bleveService) copyFrom(reader store.KVReader) error {
+	_, target, err := bs.index.Advanced()
+	if err != nil {
+		return err
+	}
+	writer, err := target.Writer()
+	if err != nil {
+		return err
+	}
+	defer writer.Close()
+	it := reader.RangeIterator(nil, nil)
+	defer it.Close()
+	batch := writer.NewBatch()
+	n := 0
+	for ; it.Valid(); it.Next() {
+		batch.Set(append([]byte(nil), it.Key()...), append([]byte(nil), it.Value()...))
+		if n++; n%snapshotBatchSize == 0 {
+			if err := writer.ExecuteBatch(batch); err != nil {
+				return err
+			}
+			batch = writer.NewBatch()
+		}
+	}
+	return writer.ExecuteBatch(batch)
+}
+
+// flushTimeout is the longest time to wait for moss to persist outstanding changes when closing
+const flushTimeout = 2 * time.Minute
+
+// flush waits until moss has persisted all outstanding changes to its lower level store, returning
+// an error if changes remain after the timeout specified. Moss persists changes asynchronously and
+// discards those not yet persisted when closed.
+func (bs *bleveService) flush(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		dirty, err := bs.dirtyOperations()
+		if err != nil || dirty == 0 {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("index has %d changes not yet persisted after %s", dirty, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+// dirtyOperations returns the number of operations not yet persisted by moss, or zero if the
+// index is not using moss
+func (bs *bleveService) dirtyOperations() (uint64, error) {
+	indexStats, _ := bs.index.StatsMap()["index"].(map[string]interface{})
+	kvStats, _ := indexStats["kv"].(map[string]interface{})
+	mossStats, ok := kvStats["moss"]
+	if !ok {
+		return 0, nil
+	}
+	data, err := json.Marshal(mossStats)
+	if err != nil {
+		return 0, err
+	}
+	var stats struct {
+		CurDirtyOps uint64
+	}
+	err = json.Unmarshal(data, &stats)
+	return stats.CurDirtyOps, err
 }
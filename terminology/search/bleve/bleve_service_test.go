@@ -0,0 +1,246 @@
+package bleve
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// extendedDescription returns an extended description for a synonym of an active clinical finding
+func extendedDescription(conceptID int64, descriptionID int64, term string, preferred bool) *snomed.ExtendedDescription {
+	ed := &snomed.ExtendedDescription{
+		Concept:            &snomed.Concept{Id: conceptID, Active: true},
+		Description:        &snomed.Description{Id: descriptionID, ConceptId: conceptID, Term: term, TypeId: int64(snomed.Synonym), Active: true},
+		RecursiveParentIds: []int64{138875005, 404684003},
+		DirectParentIds:    []int64{404684003},
+	}
+	ed.PreferredDescription = ed.Description
+	if !preferred {
+		ed.PreferredDescription = &snomed.Description{}
+	}
+	return ed
+}
+
+// newTestIndex creates an index of the descriptions specified in a temporary directory,
+// returning the index and a function to close and remove it
+func newTestIndex(t *testing.T, eds ...*snomed.ExtendedDescription) (*bleveService, func()) {
+	dir, err := ioutil.TempDir("", "bleve-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(filepath.Join(dir, "index"), false)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	bs := s.(*bleveService)
+	if err := bs.Index(eds); err != nil {
+		bs.Close()
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return bs, func() {
+		bs.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// descriptionIDs returns the set of description identifiers found by a search
+func descriptionIDs(result []int64) map[int64]bool {
+	ids := make(map[int64]bool)
+	for _, id := range result {
+		ids[id] = true
+	}
+	return ids
+}
+
+func TestLanguageReferenceSets(t *testing.T) {
+	gb := extendedDescription(80146002, 1021015, "Appendicectomy", true)
+	gb.PreferredIn = []int64{999001261000000100}
+	us := extendedDescription(80146002, 1022010, "Appendectomy", false)
+	us.PreferredIn = []int64{900000000000509007}
+	bs, done := newTestIndex(t, gb, us)
+	defer done()
+
+	result, err := bs.SearchContext(context.Background(), &snomed.SearchRequest{Search: "append", LanguageReferenceSetIds: []int64{999001261000000100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := descriptionIDs(result); len(ids) != 1 || !ids[1021015] {
+		t.Fatalf("expected only descriptions in the language reference set, got %v", result)
+	}
+
+	// an index built without the language reference sets of each description is not filtered by them
+	bs.languageRefsets = false
+	result, err = bs.SearchContext(context.Background(), &snomed.SearchRequest{Search: "append", LanguageReferenceSetIds: []int64{999001261000000100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 {
+		t.Fatalf("expected language reference sets to be ignored for an older index, got %v", result)
+	}
+}
+
+func TestExpansionsEnglish(t *testing.T) {
+	ed := extendedDescription(67782005, 1012015, "Acute respiratory distress syndrome", true)
+	ed.Description.LanguageCode = "en"
+	bs, done := newTestIndex(t, ed)
+	defer done()
+	// the English analyser stems "ards" to "ard", so the expansion must be found by the word as written
+	bs.expansions = expansions{"ards": {"acute respiratory distress syndrome"}}
+	result, err := bs.SearchContext(context.Background(), &snomed.SearchRequest{Search: "ARDS", AcceptedLanguages: "en-GB"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ids := descriptionIDs(result); !ids[1012015] {
+		t.Fatalf("expected abbreviation to be expanded, got %v", result)
+	}
+}
+
+func TestCollapseConceptsPaging(t *testing.T) {
+	// several equally-ranked descriptions for each concept, so that collapsing must fetch more
+	// than one page of descriptions
+	var eds []*snomed.ExtendedDescription
+	for i := int64(1); i <= 10; i++ {
+		term := "Pain" + strings.Repeat(" finding", int(i))
+		for j := int64(0); j < 5; j++ {
+			eds = append(eds, extendedDescription(i*100, i*100+j, term, j == 0))
+		}
+	}
+	bs, done := newTestIndex(t, eds...)
+	defer done()
+
+	request := &snomed.SearchRequest{Search: "pain", MaximumHits: 3, CollapseConcepts: true}
+	result, err := bs.SearchHits(context.Background(), request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var all []int64
+	seen := make(map[int64]bool)
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("too many pages of results")
+		}
+		if len(result.Hits) == 0 || len(result.Hits) > 3 {
+			t.Fatalf("unexpected page of %d hits", len(result.Hits))
+		}
+		for _, hit := range result.Hits {
+			if seen[hit.ConceptID] {
+				t.Fatalf("concept %d returned more than once", hit.ConceptID)
+			}
+			seen[hit.ConceptID] = true
+			all = append(all, hit.ConceptID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		if result, err = bs.SearchHits(context.Background(), &snomed.SearchRequest{Search: "pain", MaximumHits: 3, CollapseConcepts: true, Cursor: result.NextCursor}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(all) != 10 {
+		t.Fatalf("expected all 10 concepts across pages, got %v", all)
+	}
+
+	// an offset skips that number of distinct concepts
+	result, err = bs.SearchHits(context.Background(), &snomed.SearchRequest{Search: "pain", MaximumHits: 3, CollapseConcepts: true, Offset: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 3 {
+		t.Fatalf("expected a page of 3 concepts at offset 4, got %d", len(result.Hits))
+	}
+	for i, hit := range result.Hits {
+		if hit.ConceptID != all[4+i] {
+			t.Fatalf("expected concepts %v at offset 4, got %v", all[4:7], result.Hits)
+		}
+	}
+}
+
+func TestIdentifierHit(t *testing.T) {
+	identified := extendedDescription(24700007, 1004012, "Test code 1004012", true)
+	other := extendedDescription(6118003, 1003018, "Other 1004012", true)
+	excluded := extendedDescription(64572001, 1001016, "Excluded 1004012", true)
+	excluded.RecursiveParentIds = []int64{138875005}
+	bs, done := newTestIndex(t, identified, other, excluded)
+	defer done()
+
+	// the identified description is first, and is not also returned as a text match
+	result, err := bs.SearchHits(context.Background(), &snomed.SearchRequest{Search: "1004012", MaximumHits: 1, RecursiveParentIds: []int64{404684003}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Hits) != 1 || result.Hits[0].DescriptionID != 1004012 || result.NextCursor == "" {
+		t.Fatalf("expected identified description first of 2, got %+v", result)
+	}
+	result, err = bs.SearchHits(context.Background(), &snomed.SearchRequest{Search: "1004012", MaximumHits: 1, RecursiveParentIds: []int64{404684003}, Cursor: result.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 2 || len(result.Hits) != 1 || result.Hits[0].DescriptionID != 1003018 || result.NextCursor != "" {
+		t.Fatalf("expected text match on the second and last page, got %+v", result)
+	}
+
+	// an identified component must match the filters of the request
+	result, err = bs.SearchHits(context.Background(), &snomed.SearchRequest{Search: "1001016", RecursiveParentIds: []int64{404684003}, Fuzzy: snomed.SearchRequest_NO_FUZZY})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 0 {
+		t.Fatalf("expected filtered identifier not to be found, got %+v", result)
+	}
+}
+
+func TestFilters(t *testing.T) {
+	core := extendedDescription(24700007, 1012015, "Multiple sclerosis", true)
+	core.Description.ModuleId = 900000000000207008
+	core.DescriptionRefsets = []int64{991411000000109}
+	uk := extendedDescription(6118003, 1003018, "Multiple sclerosis of spinal cord", true)
+	uk.Description.ModuleId = 999000011000000103
+	uk.ConceptRefsets = []int64{991381000000107}
+	bs, done := newTestIndex(t, core, uk)
+	defer done()
+
+	tests := []struct {
+		request  *snomed.SearchRequest
+		expected int64
+	}{
+		{&snomed.SearchRequest{ModuleIds: []int64{999000011000000103}}, 1003018},
+		{&snomed.SearchRequest{DescriptionReferenceSetIds: []int64{991411000000109}}, 1012015},
+		{&snomed.SearchRequest{ExcludeReferenceSetIds: []int64{991381000000107}}, 1012015},
+	}
+	for _, test := range tests {
+		test.request.Search = "multiple sclerosis"
+		test.request.Fuzzy = snomed.SearchRequest_NO_FUZZY
+		result, err := bs.SearchContext(context.Background(), test.request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != 1 || result[0] != test.expected {
+			t.Errorf("%v: expected only %d, got %v", test.request, test.expected, result)
+		}
+	}
+}
+
+func TestIncludeRecursiveParents(t *testing.T) {
+	parent := extendedDescription(404684003, 1004012, "Clinical finding", true)
+	parent.RecursiveParentIds = []int64{138875005}
+	child := extendedDescription(64572001, 1006014, "Disease finding", true)
+	bs, done := newTestIndex(t, parent, child)
+	defer done()
+
+	for include, expected := range map[bool]int{false: 1, true: 2} {
+		result, err := bs.SearchContext(context.Background(), &snomed.SearchRequest{Search: "finding", RecursiveParentIds: []int64{404684003}, IncludeRecursiveParents: include})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result) != expected {
+			t.Errorf("including recursive parents %t: expected %d results, got %v", include, expected, result)
+		}
+	}
+}
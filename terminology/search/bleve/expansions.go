@@ -0,0 +1,78 @@
+package bleve
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	unicodetokenizer "github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/wardle/go-terminology/terminology/search"
+)
+
+// expansions is a query expansion dictionary, mapping a lower-case word without diacritics,
+// such as an abbreviation, to one or more alternative phrases to be searched in its place.
+// Words are looked up before analysis, which would otherwise stem them or remove stop words.
+type expansions map[string][]string
+
+// expansionBoost is the weighting of a match on an expansion relative to a match on
+// the original token, so that exact matches are ranked first.
+const expansionBoost = 0.5
+
+// loadExpansions reads the query expansion dictionary at the specified location.
+// A missing dictionary is not an error and results in no expansions.
+func loadExpansions(path string) (expansions, error) {
+	if path == "" {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	exps, err := readExpansions(f)
+	if err != nil {
+		return nil, fmt.Errorf("could not read query expansions from %s: %v", path, err)
+	}
+	return exps, nil
+}
+
+// readExpansions parses a query expansion dictionary. Each line contains a single token and
+// an alternative phrase separated by '=', such as "MI = myocardial infarction". A token may
+// be listed more than once to provide multiple alternatives. Tokens are case-insensitive and ignore diacritics.
+// Blank lines and lines starting with '#' are ignored.
+func readExpansions(r io.Reader) (expansions, error) {
+	exps := make(expansions)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, "=", 2)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected 'token = expansion'", n)
+		}
+		token := search.Fold(strings.ToLower(strings.TrimSpace(fields[0])))
+		expansion := strings.TrimSpace(fields[1])
+		if token == "" || expansion == "" || strings.ContainsAny(token, " \t") {
+			return nil, fmt.Errorf("line %d: invalid expansion '%s'", n, line)
+		}
+		exps[token] = append(exps[token], expansion)
+	}
+	return exps, scanner.Err()
+}
+
+// searchWords returns the lower-case word, without diacritics, at each position of the search string,
+// for finding expansions. Positions are those of the tokens produced by each analyser.
+func searchWords(s string) map[int]string {
+	result := make(map[int]string)
+	for _, token := range unicodetokenizer.NewUnicodeTokenizer().Tokenize([]byte(s)) {
+		result[token.Position] = search.Fold(strings.ToLower(string(token.Term)))
+	}
+	return result
+}
@@ -0,0 +1,51 @@
+package bleve
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadExpansions(t *testing.T) {
+	exps, err := readExpansions(strings.NewReader(`
+# comment
+MI = myocardial infarction
+CVA=cerebrovascular accident
+cva = stroke
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := expansions{
+		"mi":  {"myocardial infarction"},
+		"cva": {"cerebrovascular accident", "stroke"},
+	}
+	if !reflect.DeepEqual(exps, expected) {
+		t.Fatalf("expected %v, got %v", expected, exps)
+	}
+	for _, invalid := range []string{"MI", "MI =", "= stroke", "heart attack = myocardial infarction"} {
+		if _, err := readExpansions(strings.NewReader(invalid)); err == nil {
+			t.Errorf("expected error for invalid expansion '%s'", invalid)
+		}
+	}
+}
+
+func TestStarterExpansions(t *testing.T) {
+	f, err := os.Open("expansions-en-GB.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	exps, err := readExpansions(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exps["t2dm"]) != 1 || exps["t2dm"][0] != "type 2 diabetes mellitus" {
+		t.Fatalf("missing expansion for T2DM: %v", exps["t2dm"])
+	}
+	exps, err = loadExpansions("does-not-exist.txt")
+	if err != nil || len(exps) != 0 {
+		t.Fatalf("expected no expansions from missing dictionary, got %v (%v)", exps, err)
+	}
+}
@@ -0,0 +1,185 @@
+package bleve
+
+import (
+	"strings"
+
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/lang/da"
+	"github.com/blevesearch/bleve/analysis/lang/de"
+	"github.com/blevesearch/bleve/analysis/lang/en"
+	"github.com/blevesearch/bleve/analysis/lang/es"
+	"github.com/blevesearch/bleve/analysis/lang/fr"
+	"github.com/blevesearch/bleve/analysis/lang/it"
+	"github.com/blevesearch/bleve/analysis/lang/nl"
+	"github.com/blevesearch/bleve/analysis/lang/pt"
+	"github.com/blevesearch/bleve/analysis/lang/sv"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	"github.com/blevesearch/bleve/analysis/token/porter"
+	unicodetokenizer "github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/registry"
+	"github.com/wardle/go-terminology/terminology/search"
+	"golang.org/x/text/language"
+)
+
+// foldName is the name of the token filter that removes accents and other diacritics
+const foldName = "fold_diacritics"
+
+// defaultDocumentType is the document type for descriptions in languages without a specific analyser,
+// and for all descriptions in indexes created before analysers were chosen by language.
+const defaultDocumentType = "bleveIndexedDocument"
+
+// defaultAnalyzer is the name of the analyser for descriptions in languages without a specific analyser
+const defaultAnalyzer = "term"
+
+// defaultLanguage is the language used to analyse a search when no accepted languages are specified
+const defaultLanguage = "en"
+
+// languageFilters are the token filters used to analyse descriptions in each language, following
+// tokenisation. Accents are removed before light stemming so that matching does not depend upon them,
+// but after snowball stemming, as the snowball stemmers expect letters such as "æ", "ø" and "å".
+var languageFilters = map[string][]string{
+	"en": {en.PossessiveName, lowercase.Name, en.StopName, foldName, porter.Name},
+	"fr": {lowercase.Name, fr.ElisionName, fr.StopName, foldName, fr.LightStemmerName},
+	"de": {lowercase.Name, de.StopName, foldName, de.LightStemmerName},
+	"es": {lowercase.Name, es.StopName, foldName, es.LightStemmerName},
+	"it": {lowercase.Name, it.ElisionName, it.StopName, foldName, it.LightStemmerName},
+	"pt": {lowercase.Name, pt.StopName, foldName, pt.LightStemmerName},
+	"da": {lowercase.Name, da.StopName, da.SnowballStemmerName, foldName},
+	"nl": {lowercase.Name, nl.StopName, nl.SnowballStemmerName, foldName},
+	"sv": {lowercase.Name, sv.StopName, sv.SnowballStemmerName, foldName},
+}
+
+func init() {
+	registry.RegisterTokenFilter(foldName, func(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+		return &foldFilter{}, nil
+	})
+}
+
+// foldFilter is a token filter that removes diacritics, so that "Ménière" matches "meniere".
+// Letters that do not decompose, such as "ø" and "æ", are replaced by their usual ASCII equivalents.
+type foldFilter struct{}
+
+func (f *foldFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	for _, token := range input {
+		token.Term = []byte(search.Fold(string(token.Term)))
+	}
+	return input
+}
+
+// languageBase returns the lower-case base language for the language code specified, such as "en" for "en-GB"
+func languageBase(code string) string {
+	return strings.ToLower(strings.SplitN(code, "-", 2)[0])
+}
+
+// languageAnalyzer returns the name of the analyser for descriptions in the language specified
+func languageAnalyzer(lang string) string {
+	return defaultAnalyzer + "_" + lang
+}
+
+// languageDocumentType returns the document type for descriptions in the language specified
+func languageDocumentType(lang string) string {
+	return defaultDocumentType + "_" + lang
+}
+
+// addAnalyzers adds the default, spelling and language-specific analysers to the index mapping
+func addAnalyzers(im *mapping.IndexMappingImpl) error {
+	if err := im.AddCustomAnalyzer(spellingAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicodetokenizer.Name,
+		"token_filters": []string{lowercase.Name},
+	}); err != nil {
+		return err
+	}
+	if err := im.AddCustomAnalyzer(defaultAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicodetokenizer.Name,
+		"token_filters": []string{lowercase.Name, foldName},
+	}); err != nil {
+		return err
+	}
+	for lang, filters := range languageFilters {
+		if err := im.AddCustomAnalyzer(languageAnalyzer(lang), map[string]interface{}{
+			"type":          custom.Name,
+			"tokenizer":     unicodetokenizer.Name,
+			"token_filters": filters,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// documentType returns the type of document for a description in the language specified,
+// if the index has a mapping for that language.
+func (bs *bleveService) documentType(code string) string {
+	if lang := languageBase(code); bs.languages[lang] {
+		return languageDocumentType(lang)
+	}
+	return defaultDocumentType
+}
+
+// queryAnalyzer returns the analyser for a search, chosen from the accepted languages, formatted
+// as per https://tools.ietf.org/html/rfc7231#section-5.3.5. The analyser for the first accepted
+// language with a specific analyser is used, or English if none are specified. Indexes without
+// language-specific analysers use the analyser for the Term field.
+func (bs *bleveService) queryAnalyzer(acceptedLanguages string) *analysis.Analyzer {
+	m := bs.index.Mapping()
+	if len(bs.languages) == 0 {
+		return m.AnalyzerNamed(m.AnalyzerNameForPath("Term"))
+	}
+	if acceptedLanguages == "" {
+		acceptedLanguages = defaultLanguage
+	}
+	tags, _, _ := language.ParseAcceptLanguage(acceptedLanguages)
+	for _, tag := range tags {
+		if lang := languageBase(tag.String()); bs.languages[lang] {
+			return m.AnalyzerNamed(languageAnalyzer(lang))
+		}
+	}
+	return m.AnalyzerNamed(defaultAnalyzer)
+}
+
+// mappedLanguages returns the languages with a document mapping in the index mapping specified
+func mappedLanguages(m mapping.IndexMapping) map[string]bool {
+	result := make(map[string]bool)
+	if im, ok := m.(*mapping.IndexMappingImpl); ok {
+		for lang := range languageFilters {
+			if _, ok := im.TypeMapping[languageDocumentType(lang)]; ok {
+				result[lang] = true
+			}
+		}
+	}
+	return result
+}
+
+// hasField determines whether the index mapping specified indexes the field of descriptions specified,
+// as fields added since an index was built are missing from its documents
+func hasField(m mapping.IndexMapping, field string) bool {
+	im, ok := m.(*mapping.IndexMappingImpl)
+	if !ok {
+		return false
+	}
+	dm := im.TypeMapping[defaultDocumentType]
+	return dm != nil && dm.Properties[field] != nil
+}
+
+// hasTermField determines whether the index mapping specified indexes the terms of descriptions in the
+// field specified, such as the fields added to support spelling suggestions and phonetic matching.
+func hasTermField(m mapping.IndexMapping, field string) bool {
+	im, ok := m.(*mapping.IndexMappingImpl)
+	if !ok {
+		return false
+	}
+	if dm := im.TypeMapping[defaultDocumentType]; dm != nil {
+		if term := dm.Properties["Term"]; term != nil {
+			for _, fm := range term.Fields {
+				if fm.Name == field {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package bleve
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLanguageBase(t *testing.T) {
+	for code, expected := range map[string]string{"en": "en", "en-GB": "en", "FR": "fr", "da-dk": "da"} {
+		if base := languageBase(code); base != expected {
+			t.Errorf("expected base language %s for %s, got %s", expected, code, base)
+		}
+	}
+}
+
+func TestDanishStemming(t *testing.T) {
+	bs, done := newTestIndex(t)
+	defer done()
+	analyzer := bs.index.Mapping().AnalyzerNamed(languageAnalyzer("da"))
+	if analyzer == nil {
+		t.Fatal("no analyser for Danish")
+	}
+	tokens := func(s string) string {
+		var result []string
+		for _, token := range analyzer.Analyze([]byte(s)) {
+			result = append(result, string(token.Term))
+		}
+		return strings.Join(result, " ")
+	}
+	for _, forms := range [][2]string{
+		{"hjernesvulst", "hjernesvulster"},
+		{"sygdom", "sygdommen"},
+		{"øjenlåg", "øjenlågene"},
+	} {
+		if a, b := tokens(forms[0]), tokens(forms[1]); a != b {
+			t.Errorf("expected '%s' and '%s' to match, got '%s' and '%s'", forms[0], forms[1], a, b)
+		}
+	}
+}
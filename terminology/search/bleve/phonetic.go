@@ -0,0 +1,79 @@
+package bleve
+
+import (
+	blevesearch "github.com/blevesearch/bleve"
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/blevesearch/bleve/analysis/analyzer/custom"
+	"github.com/blevesearch/bleve/analysis/token/lowercase"
+	unicodetokenizer "github.com/blevesearch/bleve/analysis/tokenizer/unicode"
+	"github.com/blevesearch/bleve/mapping"
+	"github.com/blevesearch/bleve/registry"
+	bquery "github.com/blevesearch/bleve/search/query"
+	"github.com/wardle/go-terminology/metaphone"
+)
+
+// phoneticField is the name of the optional field containing a phonetic encoding of the words of each term
+const phoneticField = "Phonetic"
+
+// phoneticAnalyzer is the name of the analyser that encodes words phonetically
+const phoneticAnalyzer = "phonetic"
+
+// phoneticFilterName is the name of the token filter that replaces each word by its Double Metaphone encodings
+const phoneticFilterName = "double_metaphone"
+
+// phoneticLength is the maximum length of a phonetic encoding, which is longer than is conventional
+// so that long drug names are better distinguished
+const phoneticLength = 6
+
+// phoneticBoost is the weighting of a phonetic match relative to a match on the token itself
+const phoneticBoost = 0.3
+
+func init() {
+	registry.RegisterTokenFilter(phoneticFilterName, func(config map[string]interface{}, cache *registry.Cache) (analysis.TokenFilter, error) {
+		return &phoneticFilter{}, nil
+	})
+}
+
+// phoneticFilter is a token filter that replaces each word by its primary Double Metaphone encoding,
+// together with its alternate encoding at the same position, if different.
+type phoneticFilter struct{}
+
+func (f *phoneticFilter) Filter(input analysis.TokenStream) analysis.TokenStream {
+	output := make(analysis.TokenStream, 0, len(input))
+	for _, token := range input {
+		primary, alternate := metaphone.DoubleMetaphone(string(token.Term), phoneticLength)
+		if primary != "" {
+			t := *token
+			t.Term = []byte(primary)
+			output = append(output, &t)
+		}
+		if alternate != "" && alternate != primary {
+			t := *token
+			t.Term = []byte(alternate)
+			output = append(output, &t)
+		}
+	}
+	return output
+}
+
+// addPhoneticAnalyzer adds the analyser for phonetic encoding to the index mapping
+func addPhoneticAnalyzer(im *mapping.IndexMappingImpl) error {
+	return im.AddCustomAnalyzer(phoneticAnalyzer, map[string]interface{}{
+		"type":          custom.Name,
+		"tokenizer":     unicodetokenizer.Name,
+		"token_filters": []string{lowercase.Name, foldName, phoneticFilterName},
+	})
+}
+
+// phoneticQueries returns, for each position in the search, queries matching the phonetic
+// encodings of the word at that position, weighted below a match on the word itself.
+func (bs *bleveService) phoneticQueries(search string) map[int][]bquery.Query {
+	result := make(map[int][]bquery.Query)
+	for _, token := range bs.index.Mapping().AnalyzerNamed(phoneticAnalyzer).Analyze([]byte(search)) {
+		phoneticQuery := blevesearch.NewTermQuery(string(token.Term))
+		phoneticQuery.SetField(phoneticField)
+		phoneticQuery.SetBoost(phoneticBoost)
+		result[token.Position] = append(result[token.Position], phoneticQuery)
+	}
+	return result
+}
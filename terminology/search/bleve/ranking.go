@@ -0,0 +1,110 @@
+package bleve
+
+import (
+	blevesearch "github.com/blevesearch/bleve"
+	bquery "github.com/blevesearch/bleve/search/query"
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// shortTermWords is the number of words in a term above which no boost is given for a short term
+const shortTermWords = 4
+
+// defaultRanking returns the weights used to rank results when none are configured
+func defaultRanking() *snomed.SearchRequest_Ranking {
+	return &snomed.SearchRequest_Ranking{
+		Preferred:    1.0,
+		Exact:        0.5,
+		Prefix:       0.5,
+		ShortTerms:   0.5,
+		ReferenceSet: 1.0,
+	}
+}
+
+// ranking are the weights used to boost results with particular features, with zero disabling a boost
+type ranking struct {
+	preferred       float64
+	exact           float64
+	prefix          float64
+	shortTerms      float64
+	referenceSet    float64
+	referenceSetIDs []int64
+}
+
+// rankingFor returns the weights for a search, taking each weight from the request, from those
+// configured for the server or from the defaults, in that order, ignoring those that are zero.
+func rankingFor(configured *snomed.SearchRequest_Ranking, requested *snomed.SearchRequest_Ranking) ranking {
+	sources := []*snomed.SearchRequest_Ranking{requested, configured, defaultRanking()}
+	weight := func(get func(*snomed.SearchRequest_Ranking) float32) float64 {
+		for _, source := range sources {
+			if w := get(source); w != 0 {
+				if w < 0 {
+					return 0
+				}
+				return float64(w)
+			}
+		}
+		return 0
+	}
+	r := ranking{
+		preferred:    weight((*snomed.SearchRequest_Ranking).GetPreferred),
+		exact:        weight((*snomed.SearchRequest_Ranking).GetExact),
+		prefix:       weight((*snomed.SearchRequest_Ranking).GetPrefix),
+		shortTerms:   weight((*snomed.SearchRequest_Ranking).GetShortTerms),
+		referenceSet: weight((*snomed.SearchRequest_Ranking).GetReferenceSet),
+	}
+	for _, source := range sources {
+		if ids := source.GetReferenceSetIds(); len(ids) > 0 {
+			r.referenceSetIDs = ids
+			break
+		}
+	}
+	return r
+}
+
+// addRanking adds optional clauses to the boolean query that boost results with the features
+// weighted by the ranking, so that results are ranked by these features as well as by relevance.
+// The tokens are the analysed words of the search.
+func addRanking(booleanQuery *bquery.BooleanQuery, r ranking, tokens []string) {
+	if r.preferred > 0 {
+		preferredQuery := blevesearch.NewTermQuery("T")
+		preferredQuery.SetField("DescriptionIsPreferred")
+		preferredQuery.SetBoost(r.preferred)
+		booleanQuery.AddShould(preferredQuery)
+	}
+	for _, token := range tokens {
+		if r.exact > 0 {
+			exactQuery := blevesearch.NewTermQuery(token)
+			exactQuery.SetField("Term")
+			exactQuery.SetBoost(r.exact)
+			booleanQuery.AddShould(exactQuery)
+		}
+		if r.prefix > 0 && len(token) >= 3 {
+			prefixQuery := blevesearch.NewPrefixQuery(token)
+			prefixQuery.SetField("Term")
+			prefixQuery.SetBoost(r.prefix)
+			booleanQuery.AddShould(prefixQuery)
+		}
+	}
+	// a term matches a clause for each number of words up to shortTermWords not fewer than its own,
+	// so that a term of a single word receives the full boost and longer terms progressively less
+	if r.shortTerms > 0 {
+		inclusive := true
+		for n := 1; n <= shortTermWords; n++ {
+			max := float64(n)
+			shortQuery := blevesearch.NewNumericRangeInclusiveQuery(nil, &max, nil, &inclusive)
+			shortQuery.SetField("WordCount")
+			shortQuery.SetBoost(r.shortTerms / shortTermWords)
+			booleanQuery.AddShould(shortQuery)
+		}
+	}
+	if r.referenceSet > 0 && len(r.referenceSetIDs) > 0 {
+		refsetQuery := blevesearch.NewDisjunctionQuery()
+		for _, refset := range r.referenceSetIDs {
+			memberQuery := blevesearch.NewTermQuery(itobs(refset))
+			memberQuery.SetField("ConceptRefsetIds")
+			refsetQuery.AddQuery(memberQuery)
+		}
+		refsetQuery.SetBoost(r.referenceSet)
+		booleanQuery.AddShould(refsetQuery)
+	}
+}
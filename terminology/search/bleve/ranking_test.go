@@ -0,0 +1,29 @@
+package bleve
+
+import (
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+func TestRankingFor(t *testing.T) {
+	defaults := rankingFor(nil, nil)
+	if defaults.preferred != float64(defaultRanking().Preferred) || len(defaults.referenceSetIDs) != 0 {
+		t.Errorf("expected default weights, got %+v", defaults)
+	}
+	configured := &snomed.SearchRequest_Ranking{Preferred: 2, ShortTerms: -1, ReferenceSetIds: []int64{991411000000109}}
+	requested := &snomed.SearchRequest_Ranking{Preferred: 3, Prefix: -1}
+	r := rankingFor(configured, requested)
+	if r.preferred != 3 {
+		t.Errorf("expected requested weight to override configured weight, got %v", r.preferred)
+	}
+	if r.prefix != 0 || r.shortTerms != 0 {
+		t.Errorf("expected negative weights to disable boosts, got %+v", r)
+	}
+	if r.exact != float64(defaultRanking().Exact) {
+		t.Errorf("expected default weight when none configured or requested, got %v", r.exact)
+	}
+	if len(r.referenceSetIDs) != 1 || r.referenceSetIDs[0] != 991411000000109 {
+		t.Errorf("expected configured reference sets, got %v", r.referenceSetIDs)
+	}
+}
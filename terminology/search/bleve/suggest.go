@@ -0,0 +1,175 @@
+package bleve
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/blevesearch/bleve/analysis"
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/search"
+)
+
+// spellingField is the name of the field containing the unstemmed words of each description,
+// from which the dictionary used for spelling suggestions is built.
+const spellingField = "Spelling"
+
+// spellingAnalyzer is the name of the analyser used for spelling suggestions, which
+// splits text into lower-case words without removing stop words or stemming.
+const spellingAnalyzer = "spelling"
+
+// minimumCorrectionLength is the length of the shortest word that will be corrected
+const minimumCorrectionLength = 3
+
+// dictionaryWord is a word from the indexed descriptions and the number of descriptions containing it
+type dictionaryWord struct {
+	word      string // the most frequent spelling
+	count     uint64 // the number of descriptions containing any spelling
+	wordCount uint64 // the number of descriptions containing the most frequent spelling
+}
+
+// dictionary is the set of words found in the indexed descriptions, keyed by the word without
+// diacritics so that words differing only by accents are considered to be the same.
+type dictionary struct {
+	words  map[string]dictionaryWord // the most frequent spelling of each word
+	sorted []string                  // the keys of words, in order, for matching prefixes
+}
+
+// newDictionary returns an empty dictionary
+func newDictionary() *dictionary {
+	return &dictionary{words: make(map[string]dictionaryWord)}
+}
+
+// add adds a word found in the number of descriptions specified
+func (d *dictionary) add(word string, count uint64) {
+	key := search.Fold(word)
+	w, ok := d.words[key]
+	if !ok {
+		d.sorted = append(d.sorted, key)
+	}
+	if count > w.wordCount {
+		w.word, w.wordCount = word, count
+	}
+	w.count += count
+	d.words[key] = w
+}
+
+// sort must be called once all words have been added
+func (d *dictionary) sort() {
+	sort.Strings(d.sorted)
+}
+
+// known determines whether the word, or a word that it begins, is in the dictionary, so that
+// incomplete words typed as part of an interactive search are not corrected.
+func (d *dictionary) known(word string) bool {
+	key := search.Fold(word)
+	if _, ok := d.words[key]; ok {
+		return true
+	}
+	i := sort.SearchStrings(d.sorted, key)
+	return i < len(d.sorted) && strings.HasPrefix(d.sorted[i], key)
+}
+
+// correct returns the most likely correct spelling of the word specified: the most frequent of the
+// words with the fewest edits, up to a maximum of one edit for short words and two for longer words.
+func (d *dictionary) correct(word string) (string, bool) {
+	key := []rune(search.Fold(word))
+	maximum := 2
+	if len(key) <= 4 {
+		maximum = 1
+	}
+	var best dictionaryWord
+	bestDistance := maximum + 1
+	for k, w := range d.words {
+		n := utf8.RuneCountInString(k)
+		if n < len(key)-maximum || n > len(key)+maximum {
+			continue
+		}
+		distance := search.EditDistance(key, []rune(k), bestDistance)
+		if distance < bestDistance || (distance == bestDistance && w.count > best.count) {
+			best, bestDistance = w, distance
+		}
+	}
+	return best.word, bestDistance <= maximum
+}
+
+// Suggest returns the search string with any misspelt words replaced by the most likely correct
+// spelling, using a dictionary of the words in the indexed descriptions. An empty string is
+// returned if no corrections are needed or none can be found.
+func (bs *bleveService) Suggest(ctx context.Context, request *snomed.SearchRequest) (string, error) {
+	dict, err := bs.dictionary()
+	if err != nil || len(dict.words) == 0 {
+		return "", err
+	}
+	var b strings.Builder
+	corrected, last := false, 0
+	for _, token := range bs.spellingAnalyzer().Analyze([]byte(request.Search)) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		word := string(token.Term)
+		if utf8.RuneCountInString(word) < minimumCorrectionLength || dict.known(word) {
+			continue
+		}
+		if correction, ok := dict.correct(word); ok {
+			b.WriteString(request.Search[last:token.Start])
+			b.WriteString(correction)
+			last, corrected = token.End, true
+		}
+	}
+	if !corrected {
+		return "", nil
+	}
+	b.WriteString(request.Search[last:])
+	return b.String(), nil
+}
+
+// spellingAnalyzer returns the analyser for spelling suggestions, or the analyser for the Term field
+// for indexes created before spelling suggestions were supported.
+func (bs *bleveService) spellingAnalyzer() *analysis.Analyzer {
+	m := bs.index.Mapping()
+	if hasTermField(m, spellingField) {
+		return m.AnalyzerNamed(spellingAnalyzer)
+	}
+	return m.AnalyzerNamed(m.AnalyzerNameForPath("Term"))
+}
+
+// dictionary returns the dictionary of words in the indexed descriptions, built from the spelling field
+// or, for indexes created before spelling suggestions were supported, from the Term field.
+// The dictionary is cached until the index changes.
+func (bs *bleveService) dictionary() (*dictionary, error) {
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if bs.dict != nil {
+		return bs.dict, nil
+	}
+	dict := newDictionary()
+	for _, field := range []string{spellingField, "Term"} {
+		if err := bs.addFieldTerms(dict, field); err != nil {
+			return nil, err
+		}
+		if len(dict.words) > 0 {
+			break
+		}
+	}
+	dict.sort()
+	bs.dict = dict
+	return dict, nil
+}
+
+// addFieldTerms adds the terms indexed for the field specified to the dictionary
+func (bs *bleveService) addFieldTerms(dict *dictionary, field string) error {
+	fd, err := bs.index.FieldDict(field)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	for {
+		entry, err := fd.Next()
+		if err != nil || entry == nil {
+			return err
+		}
+		dict.add(entry.Term, entry.Count)
+	}
+}
@@ -0,0 +1,36 @@
+package bleve
+
+import "testing"
+
+func TestDictionary(t *testing.T) {
+	dict := newDictionary()
+	dict.add("diarrhoea", 50)
+	dict.add("parkinson's", 20)
+	dict.add("disease", 500)
+	dict.add("ménière's", 5)
+	dict.add("meniere's", 1)
+	dict.sort()
+
+	for _, word := range []string{"disease", "dise", "diarrh", "meniere's", "ménière"} {
+		if !dict.known(word) {
+			t.Errorf("expected %s to be known", word)
+		}
+	}
+	tests := map[string]string{
+		"diarrea":    "diarrhoea",
+		"parkinsons": "parkinson's",
+		"desease":    "disease",
+		"menieres":   "ménière's",
+	}
+	for word, expected := range tests {
+		if dict.known(word) {
+			t.Errorf("did not expect %s to be known", word)
+		}
+		if correction, ok := dict.correct(word); !ok || correction != expected {
+			t.Errorf("expected %s to be corrected to %s, got %s", word, expected, correction)
+		}
+	}
+	if correction, ok := dict.correct("xylophone"); ok {
+		t.Errorf("did not expect a correction for xylophone, got %s", correction)
+	}
+}
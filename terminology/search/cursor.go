@@ -0,0 +1,26 @@
+package search
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// EncodeCursor returns an opaque cursor recording the position of the next page of
+// results and the fuzziness used, so that subsequent pages are consistent with the first.
+func EncodeCursor(offset int, fuzzy snomed.SearchRequest_Fuzzy) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", offset, fuzzy)))
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor
+func DecodeCursor(cursor string) (offset int, fuzzy snomed.SearchRequest_Fuzzy, err error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor: %v", err)
+	}
+	if _, err := fmt.Sscanf(string(data), "%d:%d", &offset, &fuzzy); err != nil || offset < 0 {
+		return 0, 0, fmt.Errorf("invalid cursor: %s", cursor)
+	}
+	return offset, fuzzy, nil
+}
@@ -0,0 +1,23 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+func TestCursor(t *testing.T) {
+	cursor := EncodeCursor(200, snomed.SearchRequest_ALWAYS_FUZZY)
+	offset, fuzzy, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != 200 || fuzzy != snomed.SearchRequest_ALWAYS_FUZZY {
+		t.Fatalf("cursor not decoded correctly. got offset: %d fuzzy: %v", offset, fuzzy)
+	}
+	for _, invalid := range []string{"not a cursor", EncodeCursor(-1, snomed.SearchRequest_NO_FUZZY)} {
+		if _, _, err := DecodeCursor(invalid); err == nil {
+			t.Fatalf("failed to flag invalid cursor: %s", invalid)
+		}
+	}
+}
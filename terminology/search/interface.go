@@ -1,6 +1,8 @@
 package search
 
 import (
+	"context"
+
 	"github.com/wardle/go-terminology/snomed"
 )
 
@@ -9,6 +11,54 @@ import (
 type Search interface {
 	// Search executes a search request and returns description identifiers
 	Search(search *snomed.SearchRequest) ([]int64, error)
+	// SearchContext executes a search request, abandoning it if the context is cancelled
+	SearchContext(ctx context.Context, search *snomed.SearchRequest) ([]int64, error)
+	// SearchHits executes a search request and returns a page of scored hits,
+	// together with the total number of matches and a cursor for the next page
+	SearchHits(ctx context.Context, search *snomed.SearchRequest) (*Result, error)
+	// Suggest returns the search string with misspelt words corrected, or an empty string if
+	// no corrections are needed
+	Suggest(ctx context.Context, search *snomed.SearchRequest) (string, error)
 	Index(extendedDescriptions []*snomed.ExtendedDescription) error
+	// Delete removes the documents for the specified descriptions from the index
+	Delete(descriptionIDs []int64) error
 	Close() error
 }
+
+// Hit is a single matching description from a search
+type Hit struct {
+	DescriptionID int64
+	ConceptID     int64
+	Score         float64
+	Highlight     string // matched term with matching fragments highlighted, if requested
+	Explanation   string // how the score was calculated, if requested
+}
+
+// Result is a page of hits from a search, ordered by descending score
+type Result struct {
+	Hits        []Hit
+	Total       uint64 // total number of matching descriptions, irrespective of pagination
+	NextCursor  string // opaque cursor for the next page of results, empty if no more results
+	Facets      []Facet
+	Explanation *Explanation // how the search was executed, if requested
+	Fuzzy       bool         // whether the hits are fuzzy matches, as requested or as a fallback from no exact matches
+}
+
+// Explanation describes how a search was analysed and executed, to help understand its results
+type Explanation struct {
+	Tokens []string // the words of the search, as analysed for matching
+	Query  string   // the query generated from the search and the filters of the request
+}
+
+// Facet provides counts of matching descriptions for a requested facet
+type Facet struct {
+	Facet  snomed.SearchRequest_Facet
+	Counts []FacetCount // ordered by descending count
+}
+
+// FacetCount is the number of matching descriptions for a single concept within a facet,
+// such as a top-level hierarchy, module or reference set.
+type FacetCount struct {
+	ConceptID int64
+	Count     int
+}
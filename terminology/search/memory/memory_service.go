@@ -0,0 +1,643 @@
+// Package memory provides a search service for SNOMED-CT that holds its index in memory,
+// for tests and small embedded uses that do not need a persistent index on disk.
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/search"
+)
+
+// document is an indexed description, with the properties used to filter and rank searches
+type document struct {
+	descriptionID      int64
+	conceptID          int64
+	term               string
+	words              []string // the distinct words of the term, in lower case and without diacritics
+	descriptionType    int64
+	moduleID           int64
+	conceptActive      bool
+	preferred          bool // whether the description is the preferred term for the concept
+	recursiveParents   map[int64]bool
+	directParents      map[int64]bool
+	conceptRefsets     map[int64]bool
+	descriptionRefsets map[int64]bool
+	languageRefsets    map[int64]bool // language reference sets in which the description is preferred or acceptable
+}
+
+var _ search.Search = (*memoryService)(nil)
+
+// memoryService is a search service for SNOMED-CT that implements the search.Search interface
+// using an inverted index of the words of each description held in memory.
+type memoryService struct {
+	mu        sync.RWMutex
+	documents map[int64]*document       // documents keyed by description identifier
+	words     map[string]map[int64]bool // the descriptions containing each word
+	sorted    []string                  // the indexed words, in order, for matching prefixes
+}
+
+// minimumPrefixLength is the length of the shortest word of a search that will match the start
+// of a word, or match approximately; shorter words must match exactly.
+const minimumPrefixLength = 3
+
+// maximumFuzziness is the greatest number of edits for a word to match approximately
+const maximumFuzziness = 2
+
+// minimumCorrectionLength is the length of the shortest word that will be corrected
+const minimumCorrectionLength = 3
+
+// the scores for matching a word exactly, by its start or approximately, and the boosts for
+// preferred terms and for short terms, such that the boost is greatest for terms of a single word
+const (
+	exactScore     = 1.0
+	prefixScore    = 0.5
+	fuzzyScore     = 0.25
+	preferredBoost = 0.5
+	shortTermBoost = 0.5
+)
+
+// New returns a new, empty, search service that holds its index in memory
+func New() search.Search {
+	return &memoryService{
+		documents: make(map[int64]*document),
+		words:     make(map[string]map[int64]bool),
+	}
+}
+
+// Index adds the descriptions specified to the index, replacing any already indexed
+func (ms *memoryService) Index(eds []*snomed.ExtendedDescription) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, ed := range eds {
+		ms.remove(ed.Description.Id)
+		doc := &document{
+			descriptionID:      ed.Description.Id,
+			conceptID:          ed.Concept.Id,
+			term:               ed.Description.Term,
+			descriptionType:    ed.Description.TypeId,
+			moduleID:           ed.Description.ModuleId,
+			conceptActive:      ed.Concept.Active,
+			preferred:          ed.Description.Id == ed.PreferredDescription.Id,
+			recursiveParents:   set(ed.RecursiveParentIds),
+			directParents:      set(ed.DirectParentIds),
+			conceptRefsets:     set(ed.ConceptRefsets),
+			descriptionRefsets: set(ed.DescriptionRefsets),
+			languageRefsets:    set(append(append([]int64{}, ed.PreferredIn...), ed.AcceptableIn...)),
+		}
+		seen := make(map[string]bool)
+		for _, t := range tokenize(doc.term) {
+			if !seen[t.word] {
+				seen[t.word] = true
+				doc.words = append(doc.words, t.word)
+			}
+		}
+		ms.documents[doc.descriptionID] = doc
+		for _, word := range doc.words {
+			if ms.words[word] == nil {
+				ms.words[word] = make(map[int64]bool)
+			}
+			ms.words[word][doc.descriptionID] = true
+		}
+	}
+	ms.sortWords()
+	return nil
+}
+
+// Delete removes the documents for the specified descriptions from the index
+func (ms *memoryService) Delete(descriptionIDs []int64) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for _, id := range descriptionIDs {
+		ms.remove(id)
+	}
+	ms.sortWords()
+	return nil
+}
+
+// remove removes the document for the specified description, if indexed
+func (ms *memoryService) remove(descriptionID int64) {
+	doc, ok := ms.documents[descriptionID]
+	if !ok {
+		return
+	}
+	for _, word := range doc.words {
+		delete(ms.words[word], descriptionID)
+		if len(ms.words[word]) == 0 {
+			delete(ms.words, word)
+		}
+	}
+	delete(ms.documents, descriptionID)
+}
+
+// sortWords updates the ordered list of indexed words, and must be called once the index has changed
+func (ms *memoryService) sortWords() {
+	ms.sorted = make([]string, 0, len(ms.words))
+	for word := range ms.words {
+		ms.sorted = append(ms.sorted, word)
+	}
+	sort.Strings(ms.sorted)
+}
+
+// Search executes a search request and returns description identifiers
+func (ms *memoryService) Search(search *snomed.SearchRequest) ([]int64, error) {
+	return ms.SearchContext(context.Background(), search)
+}
+
+// SearchContext executes a search request and returns description identifiers,
+// abandoning the search if the context is cancelled or its deadline passes.
+func (ms *memoryService) SearchContext(ctx context.Context, search *snomed.SearchRequest) ([]int64, error) {
+	result, err := ms.SearchHits(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]int64, len(result.Hits))
+	for i, hit := range result.Hits {
+		results[i] = hit.DescriptionID
+	}
+	return results, nil
+}
+
+// SearchHits executes a search request and returns a page of scored hits, starting at
+// the position given by the request cursor or, if there is no cursor, the request offset.
+// Words in the search match words in the description exactly, by their start or, if fuzzy,
+// approximately. Query expansions, phonetic matching and configurable ranking are not supported.
+func (ms *memoryService) SearchHits(ctx context.Context, request *snomed.SearchRequest) (*search.Result, error) {
+	if request.Search == "" {
+		return nil, fmt.Errorf("No search string in request")
+	}
+
+	offset := int(request.Offset)
+	if request.Cursor != "" {
+		var err error
+		if offset, request.Fuzzy, err = search.DecodeCursor(request.Cursor); err != nil {
+			return nil, err
+		}
+		request.Offset, request.Cursor = int32(offset), ""
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid offset: %d", offset)
+	}
+
+	if len(request.RecursiveParentIds) == 0 {
+		request.RecursiveParentIds = []int64{138875005}
+	}
+
+	if request.MaximumHits == 0 {
+		request.MaximumHits = 200
+	}
+
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.searchHits(ctx, request, offset)
+}
+
+// searchHits executes a search request, once the request has been validated and defaults applied
+func (ms *memoryService) searchHits(ctx context.Context, request *snomed.SearchRequest, offset int) (*search.Result, error) {
+	tokens := tokenize(request.Search)
+	matched, err := ms.match(ctx, request, tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	// a search for a valid identifier finds the identified component first, ahead of the text results
+	identified := ms.identified(request)
+	if len(matched) == 0 && identified == nil && request.Fuzzy == snomed.SearchRequest_FALLBACK_FUZZY {
+		request.Fuzzy = snomed.SearchRequest_ALWAYS_FUZZY
+		return ms.searchHits(ctx, request, offset)
+	}
+
+	docs := make([]*document, 0, len(matched)+1)
+	if identified != nil {
+		docs = append(docs, identified)
+		delete(matched, identified.descriptionID)
+	}
+	textDocs := make([]*document, 0, len(matched))
+	for id := range matched {
+		textDocs = append(textDocs, ms.documents[id])
+	}
+	sort.Slice(textDocs, func(i, j int) bool {
+		a, b := textDocs[i], textDocs[j]
+		if matched[a.descriptionID] != matched[b.descriptionID] {
+			return matched[a.descriptionID] > matched[b.descriptionID]
+		}
+		return a.descriptionID < b.descriptionID
+	})
+	docs = append(docs, textDocs...)
+
+	result := &search.Result{Total: uint64(len(docs)), Fuzzy: request.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY}
+	if request.Explain {
+		result.Explanation = explainQuery(tokens, request.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY)
+	}
+	if request.CollapseConcepts {
+		docs = collapseConcepts(docs)
+	}
+	end := offset + int(request.MaximumHits)
+	if end > len(docs) {
+		end = len(docs)
+	}
+	for i := offset; i < end; i++ {
+		doc := docs[i]
+		hit := search.Hit{DescriptionID: doc.descriptionID, ConceptID: doc.conceptID, Score: matched[doc.descriptionID]}
+		if request.Highlight {
+			hit.Highlight = ms.highlight(doc.term, tokens, request.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY)
+		}
+		if request.Explain {
+			hit.Explanation = explainScore(doc, hit.Score, doc == identified)
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if len(result.Hits) > 0 && end < len(docs) {
+		result.NextCursor = search.EncodeCursor(end, request.Fuzzy)
+	}
+	for _, facet := range request.Facets {
+		result.Facets = append(result.Facets, ms.facetCounts(facet, request, textDocs))
+	}
+	return result, nil
+}
+
+// match returns the score of each description matching every word of the search and the filters
+// of the request, or no descriptions if the search has no words.
+func (ms *memoryService) match(ctx context.Context, request *snomed.SearchRequest, tokens []token) (map[int64]float64, error) {
+	var scores map[int64]float64
+	fuzzy := request.Fuzzy == snomed.SearchRequest_ALWAYS_FUZZY
+	for i, t := range tokens {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tokenScores := make(map[int64]float64)
+		for word, score := range ms.matchWords(t.word, fuzzy) {
+			for id := range ms.words[word] {
+				if score > tokenScores[id] {
+					tokenScores[id] = score
+				}
+			}
+		}
+		if i == 0 {
+			scores = tokenScores
+			continue
+		}
+		for id := range scores {
+			if score, ok := tokenScores[id]; ok {
+				scores[id] += score
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+	excluded, concepts := excludedDescriptionTypes(request), set(request.ConceptIds)
+	for id, score := range scores {
+		doc := ms.documents[id]
+		if !doc.matches(request, excluded, concepts) {
+			delete(scores, id)
+			continue
+		}
+		if doc.preferred {
+			score += preferredBoost
+		}
+		scores[id] = score + shortTermBoost/float64(len(doc.words))
+	}
+	return scores, nil
+}
+
+// matchWords returns the indexed words matching a word of the search, and the score of each match.
+// Short words must match exactly.
+func (ms *memoryService) matchWords(word string, fuzzy bool) map[string]float64 {
+	result := make(map[string]float64)
+	if _, ok := ms.words[word]; ok {
+		result[word] = exactScore
+	}
+	if len(word) < minimumPrefixLength {
+		return result
+	}
+	for i := sort.SearchStrings(ms.sorted, word); i < len(ms.sorted) && strings.HasPrefix(ms.sorted[i], word); i++ {
+		if ms.sorted[i] != word {
+			result[ms.sorted[i]] = prefixScore
+		}
+	}
+	if fuzzy {
+		w := []rune(word)
+		for _, indexed := range ms.sorted {
+			if _, ok := result[indexed]; ok {
+				continue
+			}
+			if search.EditDistance(w, []rune(indexed), maximumFuzziness) <= maximumFuzziness {
+				result[indexed] = fuzzyScore
+			}
+		}
+	}
+	return result
+}
+
+// matches determines whether the document satisfies the filters of the search request,
+// with the description types excluded and the concepts specified by the request given as sets
+func (doc *document) matches(request *snomed.SearchRequest, excludedTypes map[int64]bool, concepts map[int64]bool) bool {
+	if excludedTypes[doc.descriptionType] || containsAny(doc.conceptRefsets, request.ExcludeReferenceSetIds) {
+		return false
+	}
+	if !request.IncludeInactive && !doc.conceptActive {
+		return false
+	}
+	if !containsAll(doc.conceptRefsets, request.ReferenceSetIds) || !containsAll(doc.descriptionRefsets, request.DescriptionReferenceSetIds) {
+		return false
+	}
+	if len(request.ModuleIds) > 0 && !set(request.ModuleIds)[doc.moduleID] {
+		return false
+	}
+	if len(request.RecursiveParentIds) > 0 && !containsAny(doc.recursiveParents, request.RecursiveParentIds) &&
+		!(request.IncludeRecursiveParents && set(request.RecursiveParentIds)[doc.conceptID]) {
+		return false
+	}
+	if len(request.LanguageReferenceSetIds) > 0 && !containsAny(doc.languageRefsets, request.LanguageReferenceSetIds) {
+		return false
+	}
+	if len(request.DirectParentIds) > 0 && !containsAny(doc.directParents, request.DirectParentIds) {
+		return false
+	}
+	return len(concepts) == 0 || concepts[doc.conceptID]
+}
+
+// identified returns the component identified by the search string, if the search is for a valid
+// identifier and the component matches the filters of the request. The preferred term is returned
+// for a concept identifier, or the description itself for a description identifier.
+func (ms *memoryService) identified(request *snomed.SearchRequest) *document {
+	s := strings.TrimSpace(request.Search)
+	if len(s) < search.MinimumIdentifierLength || len(s) > search.MaximumIdentifierLength {
+		return nil
+	}
+	id, err := snomed.ParseValidIdentifier(s, true)
+	if err != nil {
+		return nil
+	}
+	excluded, concepts := excludedDescriptionTypes(request), set(request.ConceptIds)
+	switch {
+	case id.IsConcept():
+		var result *document
+		for _, doc := range ms.documents {
+			if doc.conceptID != id.Integer() || !doc.matches(request, excluded, concepts) {
+				continue
+			}
+			if result == nil || (doc.preferred && !result.preferred) || (doc.preferred == result.preferred && doc.descriptionID < result.descriptionID) {
+				result = doc
+			}
+		}
+		return result
+	case id.IsDescription():
+		if doc, ok := ms.documents[id.Integer()]; ok && doc.matches(request, excluded, concepts) {
+			return doc
+		}
+	}
+	return nil
+}
+
+// explainQuery describes the query for the words of a search: each word must match a word of a term
+// exactly ("word"), by its start ("word*") or, if fuzzy, approximately ("word~2").
+func explainQuery(tokens []token, fuzzy bool) *search.Explanation {
+	result := &search.Explanation{}
+	clauses := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		result.Tokens = append(result.Tokens, t.word)
+		alternatives := []string{t.word}
+		if len(t.word) >= minimumPrefixLength {
+			alternatives = append(alternatives, t.word+"*")
+			if fuzzy {
+				alternatives = append(alternatives, fmt.Sprintf("%s~%d", t.word, maximumFuzziness))
+			}
+		}
+		clauses = append(clauses, "+("+strings.Join(alternatives, " ")+")")
+	}
+	result.Query = strings.Join(clauses, " ")
+	return result
+}
+
+// explainScore describes how the score for a document was calculated
+func explainScore(doc *document, score float64, identified bool) string {
+	if identified {
+		return "identified by the search"
+	}
+	short := shortTermBoost / float64(len(doc.words))
+	preferred := 0.0
+	if doc.preferred {
+		preferred = preferredBoost
+	}
+	return fmt.Sprintf("%.3f = %.3f for matching words + %.3f for the preferred term + %.3f for a term of %d words", score, score-preferred-short, preferred, short, len(doc.words))
+}
+
+// highlight returns the term with the words that match the search marked
+func (ms *memoryService) highlight(term string, tokens []token, fuzzy bool) string {
+	matching := make(map[string]bool)
+	for _, t := range tokens {
+		for word := range ms.matchWords(t.word, fuzzy) {
+			matching[word] = true
+		}
+	}
+	var b strings.Builder
+	last := 0
+	for _, t := range tokenize(term) {
+		if matching[t.word] {
+			b.WriteString(term[last:t.start])
+			b.WriteString("<mark>")
+			b.WriteString(term[t.start:t.end])
+			b.WriteString("</mark>")
+			last = t.end
+		}
+	}
+	b.WriteString(term[last:])
+	return b.String()
+}
+
+// collapseConcepts returns the first document for each distinct concept, in order
+func collapseConcepts(docs []*document) []*document {
+	var result []*document
+	seen := make(map[int64]bool)
+	for _, doc := range docs {
+		if !seen[doc.conceptID] {
+			seen[doc.conceptID] = true
+			result = append(result, doc)
+		}
+	}
+	return result
+}
+
+// facetCounts returns counts of the matching descriptions for the facet specified, ordered by descending count
+func (ms *memoryService) facetCounts(facet snomed.SearchRequest_Facet, request *snomed.SearchRequest, docs []*document) search.Facet {
+	counts := make(map[int64]int)
+	for _, doc := range docs {
+		switch facet {
+		case snomed.SearchRequest_HIERARCHY:
+			for id := range doc.recursiveParents {
+				counts[id]++
+			}
+		case snomed.SearchRequest_MODULE:
+			counts[doc.moduleID]++
+		case snomed.SearchRequest_REFERENCE_SET:
+			for id := range doc.conceptRefsets {
+				counts[id]++
+			}
+		}
+	}
+	var include map[int64]bool // nil to include all
+	switch facet {
+	case snomed.SearchRequest_HIERARCHY:
+		include = ms.topLevelConcepts()
+	case snomed.SearchRequest_REFERENCE_SET:
+		include = set(request.FacetReferenceSetIds)
+	}
+	result := search.Facet{Facet: facet}
+	for id, count := range counts {
+		if include == nil || include[id] {
+			result.Counts = append(result.Counts, search.FacetCount{ConceptID: id, Count: count})
+		}
+	}
+	sort.Slice(result.Counts, func(i, j int) bool {
+		if result.Counts[i].Count != result.Counts[j].Count {
+			return result.Counts[i].Count > result.Counts[j].Count
+		}
+		return result.Counts[i].ConceptID < result.Counts[j].ConceptID
+	})
+	return result
+}
+
+// topLevelConcepts returns the identifiers of the active concepts that are direct children of the root concept
+func (ms *memoryService) topLevelConcepts() map[int64]bool {
+	result := make(map[int64]bool)
+	for _, doc := range ms.documents {
+		if doc.conceptActive && doc.directParents[int64(snomed.Root)] {
+			result[doc.conceptID] = true
+		}
+	}
+	return result
+}
+
+// Suggest returns the search string with any misspelt words replaced by the most likely correct
+// spelling, from the words in the indexed descriptions. An empty string is returned if no
+// corrections are needed or none can be found.
+func (ms *memoryService) Suggest(ctx context.Context, request *snomed.SearchRequest) (string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	var b strings.Builder
+	corrected, last := false, 0
+	for _, t := range tokenize(request.Search) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		if utf8.RuneCountInString(t.word) < minimumCorrectionLength || ms.known(t.word) {
+			continue
+		}
+		if correction, ok := ms.correct(t.word); ok {
+			b.WriteString(request.Search[last:t.start])
+			b.WriteString(correction)
+			last, corrected = t.end, true
+		}
+	}
+	if !corrected {
+		return "", nil
+	}
+	b.WriteString(request.Search[last:])
+	return b.String(), nil
+}
+
+// known determines whether the word, or a word that it begins, has been indexed
+func (ms *memoryService) known(word string) bool {
+	i := sort.SearchStrings(ms.sorted, word)
+	return i < len(ms.sorted) && strings.HasPrefix(ms.sorted[i], word)
+}
+
+// correct returns the indexed word with the fewest edits from the word specified, preferring the word
+// found in the most descriptions, up to a maximum of one edit for short words and two for longer words.
+func (ms *memoryService) correct(word string) (string, bool) {
+	w := []rune(word)
+	maximum := 2
+	if len(w) <= 4 {
+		maximum = 1
+	}
+	best, bestDistance := "", maximum+1
+	for _, indexed := range ms.sorted {
+		distance := search.EditDistance(w, []rune(indexed), bestDistance)
+		if distance < bestDistance || (distance == bestDistance && len(ms.words[indexed]) > len(ms.words[best])) {
+			best, bestDistance = indexed, distance
+		}
+	}
+	return best, bestDistance <= maximum
+}
+
+// Close releases the index
+func (ms *memoryService) Close() error {
+	return nil
+}
+
+// token is a word of a term or search, and its position within the original text
+type token struct {
+	word       string // the word in lower case, without diacritics
+	start, end int    // the byte offsets of the word within the original text
+}
+
+// tokenize splits text into words, each a sequence of letters and numbers
+func tokenize(s string) []token {
+	var result []token
+	start := -1
+	for i, r := range s {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.Mn, r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			result = append(result, newToken(s, start, i))
+			start = -1
+		}
+	}
+	if start >= 0 {
+		result = append(result, newToken(s, start, len(s)))
+	}
+	return result
+}
+
+func newToken(s string, start int, end int) token {
+	return token{word: search.Fold(strings.ToLower(s[start:end])), start: start, end: end}
+}
+
+// excludedDescriptionTypes returns the set of description types to be excluded from the search
+func excludedDescriptionTypes(request *snomed.SearchRequest) map[int64]bool {
+	result := make(map[int64]bool)
+	for _, t := range search.ExcludedDescriptionTypes(request) {
+		result[int64(t)] = true
+	}
+	return result
+}
+
+// set returns a set of the identifiers specified
+func set(ids []int64) map[int64]bool {
+	result := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		result[id] = true
+	}
+	return result
+}
+
+// containsAll determines whether the set contains every one of the identifiers specified
+func containsAll(s map[int64]bool, ids []int64) bool {
+	for _, id := range ids {
+		if !s[id] {
+			return false
+		}
+	}
+	return true
+}
+
+// containsAny determines whether the set contains any of the identifiers specified
+func containsAny(s map[int64]bool, ids []int64) bool {
+	for _, id := range ids {
+		if s[id] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// extendedDescription returns an extended description for a synonym of an active clinical finding
+func extendedDescription(conceptID int64, descriptionID int64, term string, preferred bool) *snomed.ExtendedDescription {
+	ed := &snomed.ExtendedDescription{
+		Concept:            &snomed.Concept{Id: conceptID, Active: true},
+		Description:        &snomed.Description{Id: descriptionID, ConceptId: conceptID, Term: term, TypeId: int64(snomed.Synonym)},
+		RecursiveParentIds: []int64{138875005, 404684003},
+		DirectParentIds:    []int64{404684003},
+	}
+	ed.PreferredDescription = ed.Description
+	if !preferred {
+		ed.PreferredDescription = &snomed.Description{}
+	}
+	return ed
+}
+
+func TestSearchHits(t *testing.T) {
+	ms := New()
+	err := ms.Index([]*snomed.ExtendedDescription{
+		extendedDescription(24700007, 41398015, "Multiple sclerosis", true),
+		extendedDescription(24700007, 1223979019, "Disseminated sclerosis", false),
+		extendedDescription(193093009, 297181019, "Ménière's disease", true),
+		extendedDescription(95883001, 1234567018, "Systemic sclerosis", true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	result, err := ms.SearchHits(ctx, &snomed.SearchRequest{Search: "meniere", Highlight: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 1 || result.Hits[0].Highlight != "<mark>Ménière</mark>'s disease" {
+		t.Fatalf("search ignoring diacritics failed: %+v", result.Hits)
+	}
+
+	request := &snomed.SearchRequest{Search: "scler", MaximumHits: 2, CollapseConcepts: true, Facets: []snomed.SearchRequest_Facet{snomed.SearchRequest_HIERARCHY}}
+	result, err = ms.SearchHits(ctx, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Total != 3 || len(result.Hits) != 2 || result.Hits[0].ConceptID == result.Hits[1].ConceptID || result.NextCursor != "" {
+		t.Fatalf("search collapsed by concept failed: %+v", result)
+	}
+	if len(result.Facets) != 1 || len(result.Facets[0].Counts) != 0 {
+		t.Fatalf("expected no counts for top-level concepts not indexed, got %+v", result.Facets)
+	}
+
+	result, err = ms.SearchHits(ctx, &snomed.SearchRequest{Search: "sclerosis", MaximumHits: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Hits) != 1 || result.NextCursor == "" {
+		t.Fatalf("expected a cursor for the next page, got %+v", result)
+	}
+	next, err := ms.SearchHits(ctx, &snomed.SearchRequest{Search: "sclerosis", MaximumHits: 1, Cursor: result.NextCursor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Hits) != 1 || next.Hits[0].DescriptionID == result.Hits[0].DescriptionID {
+		t.Fatalf("next page did not follow the first: %+v", next)
+	}
+
+	explained, err := ms.SearchHits(ctx, &snomed.SearchRequest{Search: "mult scl", Explain: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explained.Explanation == nil || explained.Explanation.Query != "+(mult mult*) +(scl scl*)" || len(explained.Hits) != 1 || explained.Hits[0].Explanation == "" {
+		t.Fatalf("search not explained: %+v", explained)
+	}
+
+	if err := ms.Delete([]int64{41398015, 1223979019}); err != nil {
+		t.Fatal(err)
+	}
+	ids, err := ms.Search(&snomed.SearchRequest{Search: "sclerosis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 1 || ids[0] != 1234567018 {
+		t.Fatalf("deleted descriptions found: %v", ids)
+	}
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/wardle/go-terminology/snomed"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MinimumIdentifierLength and MaximumIdentifierLength are the lengths of a valid SNOMED-CT identifier
+const (
+	MinimumIdentifierLength = 6
+	MaximumIdentifierLength = 18
+)
+
+// ExcludedDescriptionTypes returns the types of description to be excluded from a search.
+// Fully specified names are excluded unless explicitly requested.
+func ExcludedDescriptionTypes(request *snomed.SearchRequest) []snomed.DescriptionTypeID {
+	var result []snomed.DescriptionTypeID
+	if !request.IncludeFullySpecifiedNames {
+		result = append(result, snomed.FullySpecifiedName)
+	}
+	if request.ExcludeSynonyms {
+		result = append(result, snomed.Synonym)
+	}
+	if request.ExcludeDefinitions {
+		result = append(result, snomed.Definition)
+	}
+	return result
+}
+
+var foldReplacer = strings.NewReplacer("æ", "ae", "ø", "o", "œ", "oe", "ß", "ss", "ł", "l", "đ", "d", "ð", "d", "þ", "th")
+
+// Fold removes diacritics from the string specified, so that "Ménière" matches "meniere".
+// Letters that do not decompose, such as "ø" and "æ", are replaced by their usual ASCII equivalents.
+func Fold(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	result, _, err := transform.String(t, foldReplacer.Replace(s))
+	if err != nil {
+		return s
+	}
+	return result
+}
+
+// EditDistance returns the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to change a into b, or a number greater than max if that is exceeded.
+func EditDistance(a, b []rune, max int) int {
+	if len(a)-len(b) > max || len(b)-len(a) > max {
+		return max + 1
+	}
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		lowest := rows[i][0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := minInt(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = minInt(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+			lowest = minInt(lowest, d)
+		}
+		if lowest > max {
+			return max + 1
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+func minInt(values ...int) int {
+	result := values[0]
+	for _, v := range values[1:] {
+		if v < result {
+			result = v
+		}
+	}
+	return result
+}
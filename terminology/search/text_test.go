@@ -0,0 +1,38 @@
+package search
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"diarrhoea", "diarrhoea", 0},
+		{"diarrea", "diarrhoea", 2},
+		{"parkinsons", "parkinson's", 1},
+		{"haert", "heart", 1},
+		{"asthma", "eczema", 3},
+	}
+	for _, test := range tests {
+		if d := EditDistance([]rune(test.a), []rune(test.b), 2); d != test.distance && !(test.distance > 2 && d == 3) {
+			t.Errorf("expected distance %d between %s and %s, got %d", test.distance, test.a, test.b, d)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := map[string]string{
+		"ménière":      "meniere",
+		"guillain":     "guillain",
+		"sjögren":      "sjogren",
+		"hjernesvulst": "hjernesvulst",
+		"øjenlåg":      "ojenlag",
+		"cœur":         "coeur",
+		"größe":        "grosse",
+	}
+	for s, expected := range tests {
+		if folded := Fold(s); folded != expected {
+			t.Errorf("expected %s to fold to %s, got %s", s, expected, folded)
+		}
+	}
+}
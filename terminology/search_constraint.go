@@ -0,0 +1,136 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package terminology
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wardle/go-terminology/ecl"
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/search"
+	"golang.org/x/text/language"
+)
+
+// constrainedSearch is a search service that limits each search to the concepts satisfying the
+// expression constraint of the request, if any. A simple constraint is applied as filters of the
+// search index, while any other is evaluated against the store to find the matching concepts.
+// A search for accepted languages, without explicit language reference sets, is limited to
+// descriptions in the installed language reference sets best matching those languages.
+type constrainedSearch struct {
+	search search.Search
+	svc    *Svc
+}
+
+func (cs *constrainedSearch) Search(request *snomed.SearchRequest) ([]int64, error) {
+	return cs.SearchContext(context.Background(), request)
+}
+
+func (cs *constrainedSearch) SearchContext(ctx context.Context, request *snomed.SearchRequest) ([]int64, error) {
+	request, ok, err := cs.constrain(ctx, request)
+	if err != nil || !ok {
+		return []int64{}, err
+	}
+	return cs.search.SearchContext(ctx, request)
+}
+
+func (cs *constrainedSearch) SearchHits(ctx context.Context, request *snomed.SearchRequest) (*search.Result, error) {
+	request, ok, err := cs.constrain(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return &search.Result{}, nil
+	}
+	return cs.search.SearchHits(ctx, request)
+}
+
+// Suggest is not limited by the constraint of the request, as suggestions ignore filters
+func (cs *constrainedSearch) Suggest(ctx context.Context, request *snomed.SearchRequest) (string, error) {
+	return cs.search.Suggest(ctx, request)
+}
+
+func (cs *constrainedSearch) Index(eds []*snomed.ExtendedDescription) error {
+	return cs.search.Index(eds)
+}
+
+func (cs *constrainedSearch) Delete(descriptionIDs []int64) error {
+	return cs.search.Delete(descriptionIDs)
+}
+
+func (cs *constrainedSearch) Close() error {
+	return cs.search.Close()
+}
+
+// maximumConstraintConcepts is the greatest number of concepts that an expression constraint,
+// which cannot be applied as filters, may match in order to limit a search
+const maximumConstraintConcepts = 10000
+
+// ErrConstraintTooBroad is returned when the expression constraint of a search matches more
+// concepts than can be used to limit the search
+var ErrConstraintTooBroad = errors.New("expression constraint matches too many concepts to limit a search")
+
+// constrain returns a copy of the request limited to the concepts satisfying its expression
+// constraint and to the language reference sets for its accepted languages, and whether any
+// concepts can match. Requests without a constraint or accepted languages are returned unchanged.
+func (cs *constrainedSearch) constrain(ctx context.Context, request *snomed.SearchRequest) (*snomed.SearchRequest, bool, error) {
+	derivedLanguages := request.AcceptedLanguages != "" && len(request.LanguageReferenceSetIds) == 0
+	if request.Ecl == "" && !derivedLanguages {
+		return request, true, nil
+	}
+	constrained := *request
+	if derivedLanguages {
+		tags, _, err := language.ParseAcceptLanguage(request.AcceptedLanguages)
+		if err != nil {
+			return nil, false, err
+		}
+		if constrained.LanguageReferenceSetIds, err = cs.svc.LanguageReferenceSets(tags); err != nil {
+			return nil, false, err
+		}
+	}
+	if request.Ecl == "" {
+		return &constrained, true, nil
+	}
+	c, err := ecl.Parse(request.Ecl)
+	if err != nil {
+		return nil, false, err
+	}
+	constrained.Ecl = ""
+	if c.Filters(&constrained) {
+		return &constrained, true, nil
+	}
+	concepts, err := ecl.New(cs.svc.Store).Concepts(ctx, c)
+	if err != nil {
+		return nil, false, err
+	}
+	constrained.ConceptIds = make([]int64, 0, len(concepts))
+	if len(request.ConceptIds) > 0 {
+		for _, id := range request.ConceptIds {
+			if concepts[id] {
+				constrained.ConceptIds = append(constrained.ConceptIds, id)
+			}
+		}
+	} else {
+		for id := range concepts {
+			constrained.ConceptIds = append(constrained.ConceptIds, id)
+		}
+	}
+	if len(constrained.ConceptIds) > maximumConstraintConcepts {
+		return nil, false, fmt.Errorf("%w: %d concepts matched, with a maximum of %d", ErrConstraintTooBroad, len(constrained.ConceptIds), maximumConstraintConcepts)
+	}
+	return &constrained, len(constrained.ConceptIds) > 0, nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package terminology
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/search"
+	"github.com/wardle/go-terminology/terminology/search/bleve"
+	"github.com/wardle/go-terminology/terminology/storage"
+)
+
+// switchableSearch is a search service that can be switched to a different index while in use.
+// Calls to the underlying service complete before it is switched and closed.
+type switchableSearch struct {
+	mu       sync.RWMutex
+	search   search.Search
+	path     string // location of the current index
+	readOnly bool
+	options  bleve.Options
+	inMemory bool // whether the index is held in memory, and so has no location and is replaced when rebuilt
+}
+
+func (ss *switchableSearch) Search(request *snomed.SearchRequest) ([]int64, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.search.Search(request)
+}
+
+func (ss *switchableSearch) SearchContext(ctx context.Context, request *snomed.SearchRequest) ([]int64, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.search.SearchContext(ctx, request)
+}
+
+func (ss *switchableSearch) SearchHits(ctx context.Context, request *snomed.SearchRequest) (*search.Result, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.search.SearchHits(ctx, request)
+}
+
+func (ss *switchableSearch) Suggest(ctx context.Context, request *snomed.SearchRequest) (string, error) {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.search.Suggest(ctx, request)
+}
+
+func (ss *switchableSearch) Index(eds []*snomed.ExtendedDescription) error {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.search.Index(eds)
+}
+
+func (ss *switchableSearch) Delete(descriptionIDs []int64) error {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.search.Delete(descriptionIDs)
+}
+
+func (ss *switchableSearch) Close() error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.search.Close()
+}
+
+// currentPath returns the location of the index currently in use
+func (ss *switchableSearch) currentPath() string {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	return ss.path
+}
+
+// snapshotter is implemented by search services that can write a consistent copy of their index
+type snapshotter interface {
+	Snapshot(path string) error
+}
+
+// snapshot writes a consistent copy of the current index to a new index at the location specified
+func (ss *switchableSearch) snapshot(path string) error {
+	ss.mu.RLock()
+	defer ss.mu.RUnlock()
+	s, ok := ss.search.(snapshotter)
+	if !ok {
+		return fmt.Errorf("search index at %s does not support snapshots", ss.path)
+	}
+	return s.Snapshot(path)
+}
+
+// switchTo opens the index at the location specified and switches to it, closing the previous index
+func (ss *switchableSearch) switchTo(path string) error {
+	s, err := bleve.New(path, ss.readOnly, ss.options)
+	if err != nil {
+		return err
+	}
+	return ss.replace(s, path)
+}
+
+// replace switches to the search service specified, at the location specified, closing the previous service
+func (ss *switchableSearch) replace(s search.Search, path string) error {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	previous := ss.search
+	ss.search, ss.path = s, path
+	return previous.Close()
+}
+
+// IndexPath returns the location of the current search index for the datastore at the path specified,
+// given the configured location of the index. An index rebuilt since the datastore was created is
+// recorded in the datastore descriptor, and is found within the same directory as the configured location.
+func IndexPath(path string, indexPath string) (string, error) {
+	desc, err := storage.ReadDescriptor(path)
+	if os.IsNotExist(err) {
+		return indexPath, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if desc.Index == "" {
+		return indexPath, nil
+	}
+	return filepath.Join(filepath.Dir(indexPath), desc.Index), nil
+}
+
+// ReloadIndex switches to the current search index, as recorded in the datastore descriptor,
+// returning whether the index was changed.
+func (svc *Svc) ReloadIndex() (bool, error) {
+	if svc.index.inMemory {
+		return false, nil
+	}
+	path, err := IndexPath(svc.path, svc.indexPath)
+	if err != nil {
+		return false, err
+	}
+	if path == svc.index.currentPath() {
+		return false, nil
+	}
+	return true, svc.index.switchTo(path)
+}
+
+// WatchIndex periodically checks whether the search index has been rebuilt, switching to the new index
+// without interrupting searches in progress. It returns when the context is cancelled.
+func (svc *Svc) WatchIndex(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := svc.ReloadIndex()
+			if err != nil {
+				log.Printf("failed to switch to rebuilt search index: %v", err)
+			} else if changed {
+				log.Printf("switched to rebuilt search index at %s", svc.index.currentPath())
+			}
+		}
+	}
+}
+
+// swapIndex records the newly built index in the datastore descriptor and switches to it.
+// The previous index is kept until the next rebuild, as other processes, such as a server,
+// continue to use it until they next check for a rebuilt index.
+func (svc *Svc) swapIndex(path string) error {
+	desc, err := storage.ReadDescriptor(svc.path)
+	if err != nil {
+		return err
+	}
+	desc.Index = filepath.Base(path)
+	if err := desc.Save(); err != nil {
+		return err
+	}
+	_, err = svc.ReloadIndex()
+	return err
+}
+
+// removePreviousIndexes removes the indexes replaced by earlier rebuilds, other than the current index
+// and any being built
+func (svc *Svc) removePreviousIndexes() error {
+	current := svc.index.currentPath()
+	paths, err := filepath.Glob(svc.indexPath + ".*")
+	if err != nil {
+		return err
+	}
+	for _, path := range append(paths, svc.indexPath) {
+		if path == current || strings.HasSuffix(path, ".build") {
+			continue
+		}
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -16,13 +16,17 @@
 package terminology
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
 	"path/filepath"
 
 	"github.com/wardle/go-terminology/snomed"
 	"github.com/wardle/go-terminology/terminology/medicine"
 	"github.com/wardle/go-terminology/terminology/search"
 	"github.com/wardle/go-terminology/terminology/search/bleve"
+	"github.com/wardle/go-terminology/terminology/search/memory"
 	"github.com/wardle/go-terminology/terminology/storage"
 	"github.com/wardle/go-terminology/terminology/storage/boltdb"
 	"golang.org/x/text/language"
@@ -34,6 +38,9 @@ type Svc struct {
 	storage.Store
 	search.Search
 	languageMatcher language.Matcher
+	path            string            // location of the persistence store
+	indexPath       string            // configured location of the search index
+	index           *switchableSearch // the search service, which may be switched to a rebuilt index
 }
 
 // Options is a struct used as an argument to terminology.New() for setting an
@@ -42,6 +49,9 @@ type Svc struct {
 type Options struct {
 	Index         string
 	IndexReadOnly bool
+	Expansions    string                        // location of the query expansion dictionary, defaults to expansions.txt in the datastore
+	Ranking       *snomed.SearchRequest_Ranking // weights for ranking search results, with defaults used for any not specified
+	InMemoryIndex bool                          // hold the search index in memory rather than on disk, such as for tests; it is empty until indexed
 }
 
 // New opens or creates a terminology service passing the specified location to
@@ -53,27 +63,54 @@ func New(path string, readOnly bool, options ...Options) (*Svc, error) {
 		return nil, err
 	}
 
+	if len(options) > 0 && options[0].InMemoryIndex {
+		index := &switchableSearch{search: memory.New(), inMemory: true}
+		svc := &Svc{Store: bolt, path: path, index: index}
+		svc.Search = &constrainedSearch{search: index, svc: svc}
+		return svc, nil
+	}
+
 	// Set default options for index and load values from options argument
 	var (
+		ranking       *snomed.SearchRequest_Ranking
 		indexPath     = filepath.Join(path, "bleve_index")
 		indexReadOnly = readOnly
+		expansions    = filepath.Join(path, "expansions.txt")
 	)
 	if len(options) > 0 {
 		indexPath = options[0].Index
 		indexReadOnly = options[0].IndexReadOnly
+		ranking = options[0].Ranking
+		if options[0].Expansions != "" {
+			expansions = options[0].Expansions
+		}
 		// Fix path if using default path
 		if path == options[0].Index {
 			indexPath = filepath.Join(path, "bleve_index")
 		}
 	}
 
-	// Creat a new instance of the "bleve" search service
-	bleve, err := bleve.New(indexPath, indexReadOnly)
+	// Use the most recently rebuilt index, if any
+	currentIndexPath, err := IndexPath(path, indexPath)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Svc{Store: bolt, Search: bleve}, nil
+	// Creat a new instance of the "bleve" search service
+	searchOptions := bleve.Options{Expansions: expansions, Ranking: ranking}
+	var current search.Search
+	if _, err := os.Stat(currentIndexPath); indexReadOnly && os.IsNotExist(err) {
+		// an index that cannot be created is empty until one is built, and switched to by ReloadIndex
+		log.Printf("search index at %s does not exist; build the index to search", currentIndexPath)
+		current = memory.New()
+	} else if current, err = bleve.New(currentIndexPath, indexReadOnly, searchOptions); err != nil {
+		return nil, err
+	}
+	index := &switchableSearch{search: current, path: currentIndexPath, readOnly: indexReadOnly, options: searchOptions}
+
+	svc := &Svc{Store: bolt, path: path, indexPath: indexPath, index: index}
+	svc.Search = &constrainedSearch{search: index, svc: svc}
+	return svc, nil
 }
 
 // Close closes any open resources in the backend implementations
@@ -168,6 +205,9 @@ func (svc *Svc) simpleLanguageMatch(descs []*snomed.Description, typeID snomed.D
 			ds = append(ds, desc)
 		}
 	}
+	if len(ds) == 0 {
+		return nil, false, nil
+	}
 	matcher := language.NewMatcher(dTags)
 	_, i, _ := matcher.Match(tags...)
 	return ds[i], true, nil
@@ -222,8 +262,14 @@ func (svc *Svc) GetAllParents(concept *snomed.Concept) ([]*snomed.Concept, error
 
 // GetAllParentIDs returns a list of the identifiers for all parents
 func (svc *Svc) GetAllParentIDs(concept *snomed.Concept) ([]int64, error) {
+	return svc.GetAllParentIDsContext(context.Background(), concept)
+}
+
+// GetAllParentIDsContext returns a list of the identifiers for all parents,
+// stopping early with the context's error if it is cancelled or its deadline passes.
+func (svc *Svc) GetAllParentIDsContext(ctx context.Context, concept *snomed.Concept) ([]int64, error) {
 	parents := make(map[int64]bool)
-	err := svc.getAllParents(concept, parents)
+	err := svc.getAllParents(ctx, concept, parents)
 	if err != nil {
 		return nil, err
 	}
@@ -236,14 +282,22 @@ func (svc *Svc) GetAllParentIDs(concept *snomed.Concept) ([]int64, error) {
 	return keys, nil
 }
 
-func (svc *Svc) getAllParents(concept *snomed.Concept, parents map[int64]bool) error {
+func (svc *Svc) getAllParents(ctx context.Context, concept *snomed.Concept, parents map[int64]bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	ps, err := svc.GetParents(concept)
 	if err != nil {
 		return err
 	}
 	for _, p := range ps {
+		if parents[p.Id] {
+			continue
+		}
 		parents[p.Id] = true
-		svc.getAllParents(p, parents)
+		if err := svc.getAllParents(ctx, p, parents); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -316,7 +370,13 @@ func (svc *Svc) GetChildrenOfKind(concept *snomed.Concept, kind int64) ([]*snome
 // GetAllChildren fetches all children of the given concept recursively.
 // Use with caution with concepts at high levels of the hierarchy.
 func (svc *Svc) GetAllChildren(concept *snomed.Concept) ([]*snomed.Concept, error) {
-	children, err := svc.GetAllChildrenIDs(concept)
+	return svc.GetAllChildrenContext(context.Background(), concept)
+}
+
+// GetAllChildrenContext fetches all children of the given concept recursively,
+// stopping early with the context's error if it is cancelled or its deadline passes.
+func (svc *Svc) GetAllChildrenContext(ctx context.Context, concept *snomed.Concept) ([]*snomed.Concept, error) {
+	children, err := svc.GetAllChildrenIDsContext(ctx, concept)
 	if err != nil {
 		return nil, err
 	}
@@ -335,6 +395,15 @@ func (svc *Svc) ConceptsForRelationship(rel *snomed.Relationship) (source *snome
 // PathsToRoot returns the different possible paths to the root SNOMED-CT concept from this one.
 // The passed in concept will be the first entry of each path, the SNOMED root will be the last.
 func (svc *Svc) PathsToRoot(concept *snomed.Concept) ([][]*snomed.Concept, error) {
+	return svc.PathsToRootContext(context.Background(), concept)
+}
+
+// PathsToRootContext returns the different possible paths to the root SNOMED-CT concept from this one,
+// stopping early with the context's error if it is cancelled or its deadline passes.
+func (svc *Svc) PathsToRootContext(ctx context.Context, concept *snomed.Concept) ([][]*snomed.Concept, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	parents, err := svc.GetParents(concept)
 	if err != nil {
 		return nil, err
@@ -344,7 +413,7 @@ func (svc *Svc) PathsToRoot(concept *snomed.Concept) ([][]*snomed.Concept, error
 		results = append(results, []*snomed.Concept{concept})
 	}
 	for _, parent := range parents {
-		parentResults, err := svc.PathsToRoot(parent)
+		parentResults, err := svc.PathsToRootContext(ctx, parent)
 		if err != nil {
 			return nil, err
 		}
@@ -376,12 +445,22 @@ func debugPath(path []*snomed.Concept) {
 // most specific (closest) match to the concept. To determine this, we use
 // the closest match of the longest path.
 func (svc *Svc) GenericiseTo(concept *snomed.Concept, generics map[int64]bool) (*snomed.Concept, bool) {
+	generic, found, _ := svc.GenericiseToContext(context.Background(), concept, generics)
+	return generic, found
+}
+
+// GenericiseToContext returns the best generic match for the given concept, as GenericiseTo,
+// but returns the context's error if it is cancelled or its deadline passes.
+func (svc *Svc) GenericiseToContext(ctx context.Context, concept *snomed.Concept, generics map[int64]bool) (*snomed.Concept, bool, error) {
 	if generics[concept.Id] {
-		return concept, true
+		return concept, true, nil
 	}
-	paths, err := svc.PathsToRoot(concept)
+	paths, err := svc.PathsToRootContext(ctx, concept)
 	if err != nil {
-		return nil, false
+		if err == ctx.Err() {
+			return nil, false, err
+		}
+		return nil, false, nil
 	}
 	var bestPath []*snomed.Concept
 	bestPos, bestLength := -1, 0
@@ -396,9 +475,9 @@ func (svc *Svc) GenericiseTo(concept *snomed.Concept, generics map[int64]bool) (
 		}
 	}
 	if bestPos == -1 {
-		return nil, false
+		return nil, false, nil
 	}
-	return bestPath[bestPos], true
+	return bestPath[bestPos], true, nil
 }
 
 // LongestPathToRoot returns the longest path to the root concept from the specified concept
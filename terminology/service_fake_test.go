@@ -16,7 +16,13 @@
 package terminology_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -24,6 +30,8 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/wardle/go-terminology/snomed"
 	"github.com/wardle/go-terminology/terminology"
+	"github.com/wardle/go-terminology/terminology/storage"
+	"github.com/wardle/go-terminology/terminology/terminologytest"
 )
 
 const (
@@ -109,3 +117,142 @@ func TestStore(t *testing.T) {
 	svc.Close()
 	os.RemoveAll(fakeDbFilename)
 }
+
+func TestBackupRestore(t *testing.T) {
+	const restoreFilename = "bolt-tests-restored.db"
+	svc, err := terminology.New(fakeDbFilename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fakeDbFilename)
+	c1 := &snomed.Concept{Id: 24700007, Active: true, DefinitionStatusId: 900000000000073002}
+	svc.Put([]*snomed.Concept{c1})
+	var archive bytes.Buffer
+	if err := svc.Backup(&archive); err != nil {
+		t.Fatal(err)
+	}
+	svc.Close()
+
+	// a corrupted archive must not be restored
+	corrupt := append([]byte(nil), archive.Bytes()...)
+	corrupt[len(corrupt)/2] ^= 0xff
+	if err := terminology.Restore(bytes.NewReader(corrupt), restoreFilename); err == nil {
+		t.Fatal("Failed to flag corrupted archive")
+	}
+	if _, err := os.Stat(restoreFilename); !os.IsNotExist(err) {
+		t.Fatal("Corrupted archive restored")
+	}
+
+	data := append([]byte(nil), archive.Bytes()...)
+	if err := terminology.Restore(&archive, restoreFilename); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(restoreFilename)
+	restored, err := terminology.New(restoreFilename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restored.Close()
+	c, err := restored.GetConcept(c1.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !proto.Equal(c1, c) {
+		t.Fatal("Concept not restored correctly!")
+	}
+
+	// a datastore that is open must not be replaced
+	if err := terminology.Restore(bytes.NewReader(data), restoreFilename); err == nil {
+		t.Fatal("Restored over an open datastore")
+	}
+	if _, err := restored.GetConcept(c1.Id); err != nil {
+		t.Fatalf("Open datastore damaged by failed restore: %v", err)
+	}
+}
+
+func TestBackupInMemoryIndex(t *testing.T) {
+	const memoryFilename = "bolt-tests-memory.db"
+	svc, err := terminology.New(memoryFilename, false, terminology.Options{InMemoryIndex: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(memoryFilename)
+	defer svc.Close()
+	var archive bytes.Buffer
+	if err := svc.Backup(&archive); err != nil {
+		t.Fatal(err)
+	}
+	gz, err := gzip.NewReader(&archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasPrefix(hdr.Name, "bleve_index") {
+			t.Fatalf("in-memory search index included in backup as %s", hdr.Name)
+		}
+	}
+}
+
+func TestIndexPath(t *testing.T) {
+	const indexFilename = "bolt-tests-index.db"
+	defer os.RemoveAll(indexFilename)
+	indexPath := filepath.Join(indexFilename, "bleve_index")
+	if path, err := terminology.IndexPath(indexFilename, indexPath); err != nil || path != indexPath {
+		t.Fatalf("incorrect index path without descriptor: %s (error: %v)", path, err)
+	}
+	if err := os.MkdirAll(indexFilename, 0771); err != nil {
+		t.Fatal(err)
+	}
+	desc, err := storage.CreateOrOpenDescriptor(indexFilename, 0.1, "Bolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	desc.Index = "bleve_index.1539856800"
+	if err := desc.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := terminology.IndexPath(indexFilename, indexPath); err != nil || path != filepath.Join(indexFilename, desc.Index) {
+		t.Fatalf("incorrect index path for rebuilt index: %s (error: %v)", path, err)
+	}
+}
+
+func TestRebuildIndex(t *testing.T) {
+	svc := terminologytest.New(t)
+	defer svc.Close()
+	original := filepath.Join(svc.Path, "bleve_index")
+	if err := svc.Index(); err != nil {
+		t.Fatal(err)
+	}
+	// the previous index is kept, as other processes may still be using it
+	first, err := terminology.IndexPath(svc.Path, original)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(original); first == original || err != nil {
+		t.Fatalf("expected previous index to be kept after switching to %s: %v", first, err)
+	}
+	if err := svc.Index(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(original); !os.IsNotExist(err) {
+		t.Fatalf("expected index replaced by an earlier rebuild to be removed: %v", err)
+	}
+	if _, err := os.Stat(first); err != nil {
+		t.Fatalf("expected previous index to be kept: %v", err)
+	}
+	results, err := svc.Search.Search(&snomed.SearchRequest{Search: "mult scl"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) == 0 || results[0] != 1012016 {
+		t.Fatalf("rebuilt index not searched: %v", results)
+	}
+}
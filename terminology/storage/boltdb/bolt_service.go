@@ -16,10 +16,14 @@
 package boltdb
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/golang/protobuf/proto"
@@ -34,6 +38,9 @@ const currentVersion = 0.2
 type boltService struct {
 	Store storage.Store
 	db    *bolt.DB
+
+	mu          sync.Mutex
+	hierarchies map[int64]int // cached size of each top-level hierarchy, if read-only
 }
 
 // Bucket structure
@@ -50,17 +57,39 @@ var (
 	nbkDescriptions        = []byte("Descriptions")        // nested bucket, containing descriptions for this concept
 )
 
+// lockTimeout is how long to wait for the lock on the database file, held while another process has
+// the database open; a reader excludes a writer and vice versa.
+const lockTimeout = 5 * time.Second
+
 var defaultOptions = &bolt.Options{
-	Timeout:    0,
+	Timeout:    lockTimeout,
 	NoGrowSync: false,
 	ReadOnly:   false,
 }
 var readOnlyOptions = &bolt.Options{
-	Timeout:    0,
+	Timeout:    lockTimeout,
 	NoGrowSync: false,
 	ReadOnly:   true,
 }
 
+// Lock takes the exclusive lock on the database at the specified location, waiting for at most the
+// timeout given, and returns a function to release it. It fails if another process has the database open.
+// There is nothing to lock, and no error, if there is no database at the location.
+func Lock(path string, timeout time.Duration) (func() error, error) {
+	filename := filepath.Join(path, "bolt.db")
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return func() error { return nil }, nil
+	}
+	db, err := bolt.Open(filename, 0644, &bolt.Options{Timeout: timeout})
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("datastore %s is in use by another process: %w", path, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return db.Close, nil
+}
+
 // New creates a new service at the specified location
 func New(path string, readOnly bool) (storage.Store, error) {
 	var service boltService
@@ -89,6 +118,9 @@ func New(path string, readOnly bool) (storage.Store, error) {
 		options = readOnlyOptions
 	}
 	service.db, err = bolt.Open(filepath.Join(path, "bolt.db"), 0644, options)
+	if err == bolt.ErrTimeout {
+		return nil, fmt.Errorf("datastore %s is in use by another process: %w", path, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +141,7 @@ func (bs *boltService) Put(components interface{}) error {
 	case []*snomed.ReferenceSetItem:
 		err = bs.putReferenceSets(components.([]*snomed.ReferenceSetItem))
 	default:
-		err = fmt.Errorf("unknown component type: %T", components)
+		err = &storage.Error{Kind: fmt.Sprintf("%T", components), Err: storage.ErrWrongComponentType}
 	}
 	return err
 }
@@ -117,27 +149,33 @@ func (bs *boltService) Put(components interface{}) error {
 // GetConcept fetches a concept with the given identifier
 func (bs *boltService) GetConcept(conceptID int64) (*snomed.Concept, error) {
 	var c snomed.Concept
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(rbkConcepts)
 		if bucket == nil {
-			return fmt.Errorf("no bucket found with name: %s", rbkConcepts)
+			return &storage.Error{Kind: "concept", ID: conceptID, Err: storage.ErrNotFound}
 		}
-		return mustReadFromBucket(bucket, conceptID, &c)
+		return mustReadFromBucket(bucket, "concept", conceptID, &c)
 	})
+	if storage.IsNotFound(err) && isWrongComponentType(conceptID, snomed.Identifier.IsConcept) {
+		err = &storage.Error{Kind: "concept", ID: conceptID, Err: storage.ErrWrongComponentType}
+	}
 	return &c, err
 }
 
 // GetConcepts returns a list of concepts with the given identifiers
 func (bs *boltService) GetConcepts(conceptIDs ...int64) ([]*snomed.Concept, error) {
 	result := make([]*snomed.Concept, len(conceptIDs))
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(rbkConcepts)
 		if bucket == nil {
-			return fmt.Errorf("no bucket found with name: %s", rbkConcepts)
+			if len(conceptIDs) == 0 {
+				return nil
+			}
+			return &storage.Error{Kind: "concept", ID: conceptIDs[0], Err: storage.ErrNotFound}
 		}
 		for i, id := range conceptIDs {
 			var c snomed.Concept
-			if err := mustReadFromBucket(bucket, id, &c); err != nil {
+			if err := mustReadFromBucket(bucket, "concept", id, &c); err != nil {
 				return err
 			}
 			result[i] = &c
@@ -149,24 +187,41 @@ func (bs *boltService) GetConcepts(conceptIDs ...int64) ([]*snomed.Concept, erro
 
 // putConcepts persists the specified concepts
 func (bs *boltService) putConcepts(concepts []*snomed.Concept) error {
-	return bs.db.Update(func(tx *bolt.Tx) error {
+	return bs.update(func(tx *bolt.Tx) error {
 		bucket, err := tx.CreateBucketIfNotExists(rbkConcepts)
 		if err != nil {
 			return err
 		}
+		stats, err := statisticsBucket(tx)
+		if err != nil {
+			return err
+		}
+		counts := make(counters)
+		changed := make([]int64, 0, len(concepts))
 		for _, c := range concepts {
+			var old snomed.Concept
+			if found, err := previous(bucket, []byte(strconv.FormatInt(c.Id, 10)), &old); err != nil {
+				return err
+			} else if found {
+				counts.addConcept(&old, -1)
+			}
 			if err = writeToBuckets(c.Id, c, bucket); err != nil {
 				return err
 			}
+			counts.addConcept(c, 1)
+			changed = append(changed, c.Id)
 		}
-		return nil
+		if err := markChanged(tx, changed, false); err != nil {
+			return err
+		}
+		return counts.apply(stats)
 	})
 }
 
 // PutDescriptions persists the specified descriptions
 // This 1) writes the description into generic components bucket and 2) adds the description id to the concept
 func (bs *boltService) putDescriptions(descriptions []*snomed.Description) error {
-	return bs.db.Update(func(tx *bolt.Tx) error {
+	return bs.update(func(tx *bolt.Tx) error {
 		rootBucket, err := tx.CreateBucketIfNotExists(rbkDescriptions)
 		if err != nil {
 			return err
@@ -175,6 +230,12 @@ func (bs *boltService) putDescriptions(descriptions []*snomed.Description) error
 		if err != nil {
 			return err
 		}
+		stats, err := statisticsBucket(tx)
+		if err != nil {
+			return err
+		}
+		counts := make(counters)
+		changed := make([]int64, 0, len(descriptions))
 		for _, d := range descriptions {
 			conceptBucket, err := propsBucket.CreateBucketIfNotExists([]byte(strconv.Itoa(int(d.ConceptId))))
 			if err != nil {
@@ -184,36 +245,50 @@ func (bs *boltService) putDescriptions(descriptions []*snomed.Description) error
 			if err != nil {
 				return nil
 			}
+			var old snomed.Description
+			if found, err := previous(rootBucket, []byte(strconv.FormatInt(d.Id, 10)), &old); err != nil {
+				return err
+			} else if found {
+				counts.addDescription(&old, -1)
+			}
 			if err := writeToBuckets(d.Id, d, descriptionsBucket, rootBucket); err != nil {
 				return err
 			}
+			counts.addDescription(d, 1)
+			changed = append(changed, d.ConceptId)
 		}
-		return nil
+		if err := markChanged(tx, changed, false); err != nil {
+			return err
+		}
+		return counts.apply(stats)
 	})
 }
 
 // GetDescription returns the description with the given identifier
 func (bs *boltService) GetDescription(descriptionID int64) (*snomed.Description, error) {
 	var c snomed.Description
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket(rbkDescriptions)
 		if bucket == nil {
-			return fmt.Errorf("no bucket found with name: %s", rbkDescriptions)
+			return &storage.Error{Kind: "description", ID: descriptionID, Err: storage.ErrNotFound}
 		}
-		return mustReadFromBucket(bucket, descriptionID, &c)
+		return mustReadFromBucket(bucket, "description", descriptionID, &c)
 	})
+	if storage.IsNotFound(err) && isWrongComponentType(descriptionID, snomed.Identifier.IsDescription) {
+		err = &storage.Error{Kind: "description", ID: descriptionID, Err: storage.ErrWrongComponentType}
+	}
 	return &c, err
 }
 
 // GetDescriptions returns the descriptions for this concept.
 func (bs *boltService) GetDescriptions(concept *snomed.Concept) ([]*snomed.Description, error) {
 	result := make([]*snomed.Description, 0)
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		bucket, err := getPropertiesBucket(tx, concept.Id, nbkDescriptions)
-		if err != nil {
+		if err != nil || bucket == nil {
 			return err
 		}
-		bucket.ForEach(func(k, v []byte) error {
+		return bucket.ForEach(func(k, v []byte) error {
 			var o snomed.Description
 			err := proto.Unmarshal(v, &o)
 			if err != nil {
@@ -222,7 +297,6 @@ func (bs *boltService) GetDescriptions(concept *snomed.Concept) ([]*snomed.Descr
 			result = append(result, &o)
 			return nil
 		})
-		return nil
 	})
 	return result, err
 }
@@ -231,7 +305,7 @@ func (bs *boltService) GetDescriptions(concept *snomed.Concept) ([]*snomed.Descr
 func (bs *boltService) GetReferenceSets(referencedComponentID int64) ([]int64, error) {
 	componentID := []byte(strconv.FormatInt(referencedComponentID, 10))
 	result := make([]int64, 0)
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		referenceBucket := tx.Bucket([]byte(rbkReferenceSets))
 		referenceBucket.ForEach(func(k, v []byte) error {
 			refsetBucket := referenceBucket.Bucket(k)
@@ -265,7 +339,7 @@ func (bs *boltService) GetParentRelationships(concept *snomed.Concept) ([]*snome
 // getRelationships returns relationships using the specified property key.
 func (bs *boltService) getRelationships(conceptID int64, key []byte) ([]*snomed.Relationship, error) {
 	result := make([]*snomed.Relationship, 0)
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		bucket, err := getPropertiesBucket(tx, conceptID, key)
 		if err != nil {
 			return err
@@ -289,11 +363,17 @@ func (bs *boltService) getRelationships(conceptID int64, key []byte) ([]*snomed.
 // PutRelationship persists the specified relationship
 // TODO(mw): add more optimisations and precaching for each relationship
 func (bs *boltService) putRelationships(relationships []*snomed.Relationship) error {
-	return bs.db.Update(func(tx *bolt.Tx) error {
+	return bs.update(func(tx *bolt.Tx) error {
 		propsBucket, err := tx.CreateBucketIfNotExists(rbkProperties)
 		if err != nil {
 			return err
 		}
+		stats, err := statisticsBucket(tx)
+		if err != nil {
+			return err
+		}
+		counts := make(counters)
+		var changed, moved []int64 // source concepts changed, and those whose place in the hierarchy has changed
 		for _, r := range relationships {
 			sourceBucket, err := propsBucket.CreateBucketIfNotExists([]byte(strconv.Itoa(int(r.SourceId))))
 			if err != nil {
@@ -311,20 +391,49 @@ func (bs *boltService) putRelationships(relationships []*snomed.Relationship) er
 			if err != nil {
 				return err
 			}
+			var old snomed.Relationship
+			if found, err := previous(sParents, []byte(strconv.FormatInt(r.Id, 10)), &old); err != nil {
+				return err
+			} else if found {
+				counts.addRelationship(&old, -1)
+			}
 			if err := writeToBuckets(r.Id, r, sParents, sChildren); err != nil {
 				return err
 			}
+			counts.addRelationship(r, 1)
+			if movesInHierarchy(&old, r) {
+				moved = append(moved, r.SourceId)
+			} else {
+				changed = append(changed, r.SourceId)
+			}
 		}
-		return nil
+		if err := markChanged(tx, moved, true); err != nil {
+			return err
+		}
+		if err := markChanged(tx, changed, false); err != nil {
+			return err
+		}
+		if len(moved) > 0 {
+			if err := clearHierarchies(stats); err != nil {
+				return err
+			}
+		}
+		return counts.apply(stats)
 	})
 }
 
 func (bs *boltService) putReferenceSets(refset []*snomed.ReferenceSetItem) error {
-	return bs.db.Update(func(tx *bolt.Tx) error {
+	return bs.update(func(tx *bolt.Tx) error {
 		referenceBucket, err := tx.CreateBucketIfNotExists(rbkReferenceSets)
 		if err != nil {
 			return err
 		}
+		stats, err := statisticsBucket(tx)
+		if err != nil {
+			return err
+		}
+		counts := make(counters)
+		changed := make([]int64, 0, len(refset))
 		for _, item := range refset {
 			refsetID := []byte(strconv.FormatInt(item.GetRefsetId(), 10))
 			referencedComponentID := []byte(strconv.FormatInt(item.GetReferencedComponentId(), 10))
@@ -336,11 +445,22 @@ func (bs *boltService) putReferenceSets(refset []*snomed.ReferenceSetItem) error
 			if err != nil {
 				return err
 			}
+			var old snomed.ReferenceSetItem
+			if found, err := previous(refSetBucket, referencedComponentID, &old); err != nil {
+				return err
+			} else if found {
+				counts.addReferenceSetItem(&old, -1)
+			}
 			if err := refSetBucket.Put(referencedComponentID, data); err != nil {
 				return err
 			}
+			counts.addReferenceSetItem(item, 1)
+			changed = append(changed, item.ReferencedComponentId)
 		}
-		return nil
+		if err := markChanged(tx, changed, false); err != nil {
+			return err
+		}
+		return counts.apply(stats)
 	})
 }
 
@@ -368,15 +488,22 @@ func readFromBucket(bucket *bolt.Bucket, id int, o proto.Message) error {
 }
 
 // read an object from a bucket, throwing an error if not found
-func mustReadFromBucket(bucket *bolt.Bucket, id int64, o proto.Message) error {
+func mustReadFromBucket(bucket *bolt.Bucket, kind string, id int64, o proto.Message) error {
 	key := []byte(strconv.FormatInt(id, 10))
 	data := bucket.Get(key)
 	if data == nil {
-		return fmt.Errorf("no object found with identifier %d", id)
+		return &storage.Error{Kind: kind, ID: id, Err: storage.ErrNotFound}
 	}
 	return proto.Unmarshal(data, o)
 }
 
+// isWrongComponentType returns whether the identifier is a valid SNOMED CT identifier
+// but one partitioned for a type of component other than that expected
+func isWrongComponentType(id int64, expected func(snomed.Identifier) bool) bool {
+	sctID := snomed.Identifier(id)
+	return id > 99 && sctID.IsValid() && !expected(sctID)
+}
+
 // helper method to write an object into multiple buckets
 func writeToBuckets(id int64, o proto.Message, buckets ...*bolt.Bucket) error {
 	data, err := proto.Marshal(o)
@@ -397,17 +524,49 @@ func (bs *boltService) Close() error {
 	return bs.db.Close()
 }
 
+// Backup writes a consistent copy of the database to the writer, without blocking other readers or writers
+func (bs *boltService) Backup(w io.Writer) (int64, error) {
+	var n int64
+	err := bs.view(func(tx *bolt.Tx) error {
+		var err error
+		n, err = tx.WriteTo(w)
+		return err
+	})
+	return n, err
+}
+
+// view executes a read-only transaction, reporting a closed database as storage.ErrStoreClosed
+func (bs *boltService) view(fn func(*bolt.Tx) error) error {
+	return storeError(bs.db.View(fn))
+}
+
+// update executes a read-write transaction, reporting a closed database as storage.ErrStoreClosed
+func (bs *boltService) update(fn func(*bolt.Tx) error) error {
+	return storeError(bs.db.Update(fn))
+}
+
+// storeError maps errors from the underlying database to those defined by the storage package
+func storeError(err error) error {
+	switch err {
+	case bolt.ErrDatabaseNotOpen:
+		return storage.ErrStoreClosed
+	case bolt.ErrDatabaseReadOnly:
+		return storage.ErrReadOnly
+	}
+	return err
+}
+
 func (bs *boltService) GetReferenceSetItems(refset int64) (map[int64]bool, error) {
 	refsetID := []byte(strconv.FormatInt(refset, 10))
 	result := make(map[int64]bool)
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		referenceBucket := tx.Bucket([]byte(rbkReferenceSets))
 		if referenceBucket == nil {
-			return fmt.Errorf("no bucket found for refsets")
+			return &storage.Error{Kind: "reference set", ID: refset, Err: storage.ErrNotFound}
 		}
 		bucket := referenceBucket.Bucket(refsetID)
 		if bucket == nil {
-			return fmt.Errorf("no bucket found with name: %d", refset)
+			return &storage.Error{Kind: "reference set", ID: refset, Err: storage.ErrNotFound}
 		}
 		err := bucket.ForEach(func(k, v []byte) error {
 			id, err := strconv.ParseInt(string(k), 10, 64)
@@ -426,16 +585,16 @@ func (bs *boltService) GetReferenceSetItems(refset int64) (map[int64]bool, error
 func (bs *boltService) GetFromReferenceSet(refset int64, component int64) (*snomed.ReferenceSetItem, error) {
 	var result snomed.ReferenceSetItem
 	found := false
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		referenceBucket := tx.Bucket([]byte(rbkReferenceSets))
 		if referenceBucket == nil {
-			return fmt.Errorf("no bucket found to store refsets")
+			return &storage.Error{Kind: "reference set", ID: refset, Err: storage.ErrNotFound}
 		}
 		bucket := referenceBucket.Bucket([]byte(strconv.Itoa(int(refset))))
 		if bucket == nil {
-			return fmt.Errorf("refset %d not installed", refset)
+			return &storage.Error{Kind: "reference set", ID: refset, Err: storage.ErrNotFound}
 		}
-		if err := mustReadFromBucket(bucket, component, &result); err == nil {
+		if err := mustReadFromBucket(bucket, "reference set item", component, &result); err == nil {
 			found = true
 		}
 		return nil
@@ -449,7 +608,7 @@ func (bs *boltService) GetFromReferenceSet(refset int64, component int64) (*snom
 // GetAllReferenceSets returns a list of installed reference sets
 func (bs *boltService) GetAllReferenceSets() ([]int64, error) {
 	result := make([]int64, 0)
-	err := bs.db.View(func(tx *bolt.Tx) error {
+	err := bs.view(func(tx *bolt.Tx) error {
 		referenceBucket := tx.Bucket([]byte(rbkReferenceSets))
 		if referenceBucket != nil {
 			referenceBucket.ForEach(func(k, v []byte) error {
@@ -470,8 +629,14 @@ func (bs *boltService) GetAllReferenceSets() ([]int64, error) {
 // This is a potentially large number, depending on where in the hierarchy the concept sits.
 // TODO(mw): change to use transitive closure table
 func (bs *boltService) GetAllChildrenIDs(concept *snomed.Concept) ([]int64, error) {
+	return bs.GetAllChildrenIDsContext(context.Background(), concept)
+}
+
+// GetAllChildrenIDsContext returns the recursive children for this concept,
+// stopping early with the context's error if it is cancelled or its deadline passes.
+func (bs *boltService) GetAllChildrenIDsContext(ctx context.Context, concept *snomed.Concept) ([]int64, error) {
 	allChildren := make(map[int64]bool)
-	err := bs.recursiveChildren(concept.Id, allChildren)
+	err := bs.recursiveChildren(ctx, concept.Id, allChildren)
 	if err != nil {
 		return nil, err
 	}
@@ -484,7 +649,10 @@ func (bs *boltService) GetAllChildrenIDs(concept *snomed.Concept) ([]int64, erro
 
 // this is a brute-force, non-cached temporary version which actually fetches the id
 // TODO(mwardle): benchmark and possibly use transitive closure precached table a la java version
-func (bs *boltService) recursiveChildren(conceptID int64, allChildren map[int64]bool) error {
+func (bs *boltService) recursiveChildren(ctx context.Context, conceptID int64, allChildren map[int64]bool) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	children, err := bs.getRelationships(conceptID, nbkChildRelationships)
 	if err != nil {
 		return err
@@ -497,7 +665,7 @@ func (bs *boltService) recursiveChildren(conceptID int64, allChildren map[int64]
 				if err != nil {
 					return err
 				}
-				err = bs.recursiveChildren(childID, allChildren)
+				err = bs.recursiveChildren(ctx, childID, allChildren)
 				if err != nil {
 					return err
 				}
@@ -509,10 +677,19 @@ func (bs *boltService) recursiveChildren(conceptID int64, allChildren map[int64]
 
 // Iterate is a crude iterator for all concepts, useful for pre-processing and pre-computations
 func (bs *boltService) Iterate(fn func(*snomed.Concept) error) error {
-	return bs.db.View(func(tx *bolt.Tx) error {
+	return bs.IterateContext(context.Background(), fn)
+}
+
+// IterateContext iterates all concepts, as Iterate, but stops with the context's error
+// if it is cancelled or its deadline passes.
+func (bs *boltService) IterateContext(ctx context.Context, fn func(*snomed.Concept) error) error {
+	return bs.view(func(tx *bolt.Tx) error {
 		bucket := tx.Bucket([]byte(rbkConcepts))
 		var concept snomed.Concept
 		return bucket.ForEach(func(k, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if err := proto.Unmarshal(v, &concept); err != nil {
 				return err
 			}
@@ -521,50 +698,86 @@ func (bs *boltService) Iterate(fn func(*snomed.Concept) error) error {
 	})
 }
 
-// GetStatistics returns statistics for the backend store
-// This is crude and inefficient at the moment
-// TODO(wardle): improve efficiency and speed
-func (bs *boltService) GetStatistics() (storage.Statistics, error) {
-	stats := storage.Statistics{}
-	refsetNames := make([]string, 0)
-	err := bs.db.View(func(tx *bolt.Tx) error {
-		// concepts
-		cBucket := tx.Bucket([]byte(rbkConcepts))
-		stats.Concepts = cBucket.Stats().KeyN
-		// descriptions
-		dBucket := tx.Bucket([]byte(rbkDescriptions))
-		stats.Descriptions = dBucket.Stats().KeyN
-
-		// reference sets
-		rs := tx.Bucket([]byte(rbkReferenceSets))
-		stats.RefsetItems = rs.Stats().KeyN
-		refsets := make([]int64, 0)
-		c := rs.Cursor()
-		for k, v := c.First(); k != nil; k, v = c.Next() {
-			if v == nil { // if value is nil, then we have a subbucket
-				id, err := strconv.ParseInt(string(k), 10, 64)
-				if err != nil {
+// IterateDescriptions iterates all descriptions matching the filter, in a single pass in identifier order.
+func (bs *boltService) IterateDescriptions(ctx context.Context, filter storage.Filter, fn func(*snomed.Description) error) error {
+	return bs.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rbkDescriptions)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var d snomed.Description
+			if err := proto.Unmarshal(v, &d); err != nil {
+				return err
+			}
+			if !filter.Matches(d.Active, d.ModuleId, d.TypeId) {
+				return nil
+			}
+			return fn(&d)
+		})
+	})
+}
+
+// IterateRelationships iterates all relationships matching the filter, in a single pass ordered by source concept.
+func (bs *boltService) IterateRelationships(ctx context.Context, filter storage.Filter, fn func(*snomed.Relationship) error) error {
+	return bs.view(func(tx *bolt.Tx) error {
+		propsBucket := tx.Bucket(rbkProperties)
+		if propsBucket == nil {
+			return nil
+		}
+		return propsBucket.ForEach(func(k, v []byte) error {
+			conceptBucket := propsBucket.Bucket(k)
+			if conceptBucket == nil {
+				return nil
+			}
+			bucket := conceptBucket.Bucket(nbkParentRelationships)
+			if bucket == nil {
+				return nil
+			}
+			return bucket.ForEach(func(k, v []byte) error {
+				if err := ctx.Err(); err != nil {
 					return err
 				}
-				refsets = append(refsets, id)
-			}
+				var r snomed.Relationship
+				if err := proto.Unmarshal(v, &r); err != nil {
+					return err
+				}
+				if !filter.Matches(r.Active, r.ModuleId, r.TypeId) {
+					return nil
+				}
+				return fn(&r)
+			})
+		})
+	})
+}
+
+// IterateReferenceSetItems iterates the items of the specified reference set matching the filter,
+// in a single pass ordered by referenced component.
+func (bs *boltService) IterateReferenceSetItems(ctx context.Context, refset int64, filter storage.Filter, fn func(*snomed.ReferenceSetItem) error) error {
+	return bs.view(func(tx *bolt.Tx) error {
+		referenceBucket := tx.Bucket(rbkReferenceSets)
+		if referenceBucket == nil {
+			return &storage.Error{Kind: "reference set", ID: refset, Err: storage.ErrNotFound}
 		}
-		concepts, err := bs.GetConcepts(refsets...)
-		if err != nil {
-			return err
+		bucket := referenceBucket.Bucket([]byte(strconv.FormatInt(refset, 10)))
+		if bucket == nil {
+			return &storage.Error{Kind: "reference set", ID: refset, Err: storage.ErrNotFound}
 		}
-		for _, c := range concepts {
-			descs, err := bs.GetDescriptions(c)
-			if err != nil {
+		return bucket.ForEach(func(k, v []byte) error {
+			if err := ctx.Err(); err != nil {
 				return err
 			}
-			if len(descs) > 0 {
-				refsetName := fmt.Sprintf("%s (%d)", descs[0].Term, c.Id)
-				refsetNames = append(refsetNames, refsetName)
+			var item snomed.ReferenceSetItem
+			if err := proto.Unmarshal(v, &item); err != nil {
+				return err
 			}
-		}
-		return err
+			if !filter.Matches(item.Active, item.ModuleId, 0) {
+				return nil
+			}
+			return fn(&item)
+		})
 	})
-	stats.Refsets = refsetNames
-	return stats, err
 }
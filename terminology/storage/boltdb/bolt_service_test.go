@@ -15,6 +15,8 @@
 package boltdb
 
 import (
+	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -23,6 +25,7 @@ import (
 	"github.com/golang/protobuf/ptypes"
 	"github.com/kylelemons/godebug/pretty"
 	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/storage"
 )
 
 const (
@@ -98,3 +101,221 @@ func TestStore(t *testing.T) {
 	bolt.Close()
 	os.RemoveAll(boltFilename)
 }
+
+func TestCancelledContext(t *testing.T) {
+	bolt, err := New(boltFilename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(boltFilename)
+	defer bolt.Close()
+	c1 := &snomed.Concept{Id: 24700007, Active: true}
+	c2 := &snomed.Concept{Id: 6118003, Active: true}
+	r1 := &snomed.Relationship{Id: 1, Active: true, SourceId: c1.Id, DestinationId: c2.Id, TypeId: snomed.IsA}
+	bolt.Put([]*snomed.Concept{c1, c2})
+	bolt.Put([]*snomed.Relationship{r1})
+	children, err := bolt.GetAllChildrenIDsContext(context.Background(), c2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 1 || children[0] != c1.Id {
+		t.Fatalf("Multiple sclerosis not found as a child of demyelinating disease. got: %v", children)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := bolt.GetAllChildrenIDsContext(ctx, c2); err != context.Canceled {
+		t.Fatalf("expected cancellation error, got: %v", err)
+	}
+	count := 0
+	err = bolt.IterateContext(ctx, func(c *snomed.Concept) error {
+		count++
+		return nil
+	})
+	if err != context.Canceled || count != 0 {
+		t.Fatalf("expected iteration to be cancelled. visited: %d, error: %v", count, err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	bolt, err := New(boltFilename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(boltFilename)
+	bolt.Put([]*snomed.Concept{{Id: 24700007, Active: true}})
+	if _, err := bolt.GetConcept(6118003); !storage.IsNotFound(err) {
+		t.Fatalf("expected not found error for missing concept, got: %v", err)
+	}
+	if _, err := bolt.GetConcept(6118003); !storage.IsNotFound(fmt.Errorf("fetching concept: %w", err)) {
+		t.Fatalf("expected not found error to be recognised when wrapped, got: %v", err)
+	}
+	if _, err := bolt.GetConcept(41398015); !storage.IsWrongComponentType(err) {
+		t.Fatalf("expected wrong component type error for description identifier, got: %v", err)
+	}
+	if err := bolt.Put([]int64{24700007}); !storage.IsWrongComponentType(err) {
+		t.Fatalf("expected wrong component type error for unsupported component, got: %v", err)
+	}
+	bolt.Close()
+	if _, err := bolt.GetConcept(24700007); !storage.IsStoreClosed(err) {
+		t.Fatalf("expected store closed error, got: %v", err)
+	}
+	readOnly, err := New(boltFilename, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer readOnly.Close()
+	if err := readOnly.ClearChanged(); !storage.IsReadOnly(err) {
+		t.Fatalf("expected read-only error, got: %v", err)
+	}
+}
+
+func TestStatistics(t *testing.T) {
+	bolt, err := New(boltFilename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(boltFilename)
+	defer bolt.Close()
+	root := &snomed.Concept{Id: int64(snomed.Root), Active: true, ModuleId: 900000000000207008}
+	c1 := &snomed.Concept{Id: 404684003, Active: true, ModuleId: 900000000000207008}
+	c2 := &snomed.Concept{Id: 24700007, Active: true, ModuleId: 900000000000207008}
+	d1 := &snomed.Description{Id: 41398015, ConceptId: 24700007, Active: true, ModuleId: 900000000000207008, TypeId: int64(snomed.Synonym), Term: "Multiple sclerosis"}
+	r1 := &snomed.Relationship{Id: 1, Active: true, SourceId: c1.Id, DestinationId: root.Id, TypeId: snomed.IsA, ModuleId: 900000000000207008}
+	r2 := &snomed.Relationship{Id: 2, Active: true, SourceId: c2.Id, DestinationId: c1.Id, TypeId: snomed.IsA, ModuleId: 900000000000207008}
+	item := &snomed.ReferenceSetItem{Id: "1", RefsetId: c2.Id, ReferencedComponentId: d1.Id, Active: true, ModuleId: 900000000000207008, Body: &snomed.ReferenceSetItem_Language{Language: &snomed.LanguageReferenceSet{}}}
+	bolt.Put([]*snomed.Concept{root, c1, c2})
+	bolt.Put([]*snomed.Description{d1})
+	bolt.Put([]*snomed.Relationship{r1, r2})
+	bolt.Put([]*snomed.ReferenceSetItem{item})
+	// replacing a component must not count it twice
+	bolt.Put([]*snomed.Concept{{Id: c2.Id, Active: false, ModuleId: 900000000000207008}})
+	stats, err := bolt.GetStatistics()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Concepts != 3 || stats.ActiveConcepts != 2 || stats.Descriptions != 1 || stats.Relationships != 2 || stats.RefsetItems != 1 {
+		t.Fatalf("incorrect counts: %+v", stats)
+	}
+	if stats.Modules[900000000000207008] != 7 {
+		t.Fatalf("incorrect module counts: %v", stats.Modules)
+	}
+	if len(stats.Hierarchies) != 1 || stats.Hierarchies[c1.Id] != 2 {
+		t.Fatalf("incorrect hierarchy counts: %v", stats.Hierarchies)
+	}
+	if stats.RefsetMembers[c2.Id] != 1 || stats.LanguageRefsets[c2.Id] != 1 || stats.Names[c2.Id] != "Multiple sclerosis" {
+		t.Fatalf("incorrect reference set statistics: %+v", stats)
+	}
+	// hierarchy counts are kept until the IS-A relationships change
+	r3 := &snomed.Relationship{Id: 3, Active: true, SourceId: 6118003, DestinationId: c1.Id, TypeId: snomed.IsA, ModuleId: 900000000000207008}
+	bolt.Put([]*snomed.Relationship{r3})
+	if stats, err = bolt.GetStatistics(); err != nil {
+		t.Fatal(err)
+	}
+	if len(stats.Hierarchies) != 1 || stats.Hierarchies[c1.Id] != 3 {
+		t.Fatalf("hierarchy counts not updated after change in relationships: %v", stats.Hierarchies)
+	}
+}
+
+func TestIterators(t *testing.T) {
+	bolt, err := New(boltFilename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(boltFilename)
+	defer bolt.Close()
+	d1 := &snomed.Description{Id: 41398015, ConceptId: 24700007, Active: true, TypeId: int64(snomed.Synonym), Term: "Multiple sclerosis"}
+	d2 := &snomed.Description{Id: 1223979019, ConceptId: 24700007, Active: false, TypeId: int64(snomed.Synonym), Term: "Disseminated sclerosis"}
+	d3 := &snomed.Description{Id: 11161017, ConceptId: 24700007, Active: true, TypeId: int64(snomed.FullySpecifiedName), Term: "Multiple sclerosis (disorder)"}
+	r1 := &snomed.Relationship{Id: 1, Active: true, SourceId: 24700007, DestinationId: 6118003, TypeId: snomed.IsA}
+	r2 := &snomed.Relationship{Id: 2, Active: true, SourceId: 24700007, DestinationId: 21483005, TypeId: snomed.FindingSite}
+	item := &snomed.ReferenceSetItem{Id: "1", RefsetId: 991411000000109, ReferencedComponentId: 24700007, Active: true, ModuleId: 999000011000000103}
+	bolt.Put([]*snomed.Description{d1, d2, d3})
+	bolt.Put([]*snomed.Relationship{r1, r2})
+	bolt.Put([]*snomed.ReferenceSetItem{item})
+	descriptions := make([]int64, 0)
+	err = bolt.IterateDescriptions(context.Background(), storage.Filter{ActiveOnly: true, TypeID: int64(snomed.Synonym)}, func(d *snomed.Description) error {
+		descriptions = append(descriptions, d.Id)
+		return nil
+	})
+	if err != nil || len(descriptions) != 1 || descriptions[0] != d1.Id {
+		t.Fatalf("incorrect descriptions iterated: %v (error: %v)", descriptions, err)
+	}
+	relationships := make([]int64, 0)
+	err = bolt.IterateRelationships(context.Background(), storage.Filter{TypeID: snomed.IsA}, func(r *snomed.Relationship) error {
+		relationships = append(relationships, r.Id)
+		return nil
+	})
+	if err != nil || len(relationships) != 1 || relationships[0] != r1.Id {
+		t.Fatalf("incorrect relationships iterated: %v (error: %v)", relationships, err)
+	}
+	count := 0
+	err = bolt.IterateReferenceSetItems(context.Background(), item.RefsetId, storage.Filter{ModuleID: item.ModuleId}, func(i *snomed.ReferenceSetItem) error {
+		count++
+		return nil
+	})
+	if err != nil || count != 1 {
+		t.Fatalf("incorrect reference set items iterated: %d (error: %v)", count, err)
+	}
+	err = bolt.IterateReferenceSetItems(context.Background(), 0, storage.Filter{}, func(i *snomed.ReferenceSetItem) error { return nil })
+	if !storage.IsNotFound(err) {
+		t.Fatalf("expected not found error for missing reference set, got: %v", err)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	bolt, err := New(boltFilename, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(boltFilename)
+	defer bolt.Close()
+	if err := bolt.MarkChanged([]int64{24700007, 41398015}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := bolt.MarkChanged([]int64{24700007}, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := bolt.MarkChanged([]int64{24700007}, false); err != nil {
+		t.Fatal(err)
+	}
+	changed, err := bolt.GetChanged()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 2 || !changed[24700007] || changed[41398015] {
+		t.Fatalf("incorrect changes recorded: %v", changed)
+	}
+	if err := bolt.ClearChanged(); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err = bolt.GetChanged(); err != nil || len(changed) != 0 {
+		t.Fatalf("changes not cleared: %v (error: %v)", changed, err)
+	}
+
+	// storing an IS-A relationship moves its source concept only if new, or if it becomes active,
+	// inactive or has a different destination
+	r1 := &snomed.Relationship{Id: 1, Active: true, SourceId: 24700007, DestinationId: 6118003, TypeId: snomed.IsA}
+	r2 := &snomed.Relationship{Id: 2, Active: true, SourceId: 6118003, DestinationId: 64572001, TypeId: snomed.IsA}
+	r3 := &snomed.Relationship{Id: 3, Active: true, SourceId: 64572001, DestinationId: 404684003, TypeId: snomed.IsA}
+	if err := bolt.Put([]*snomed.Relationship{r1, r2, r3}); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err = bolt.GetChanged(); err != nil || len(changed) != 3 || !changed[24700007] {
+		t.Fatalf("new relationships not recorded as moving their source concepts: %v (error: %v)", changed, err)
+	}
+	if err := bolt.ClearChanged(); err != nil {
+		t.Fatal(err)
+	}
+	r2.DestinationId = 404684003
+	r3.Active = false
+	if err := bolt.Put([]*snomed.Relationship{r1, r2, r3}); err != nil {
+		t.Fatal(err)
+	}
+	changed, err = bolt.GetChanged()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 3 || changed[24700007] || !changed[6118003] || !changed[64572001] {
+		t.Fatalf("incorrect hierarchy changes recorded: %v", changed)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package boltdb
+
+import (
+	"strconv"
+
+	"github.com/boltdb/bolt"
+	"github.com/wardle/go-terminology/snomed"
+)
+
+// rbkChanges is a root bucket containing the identifiers of components changed since the search index
+// was last updated, with a value of 1 if the component's place in the hierarchy changed, or 0 otherwise.
+var rbkChanges = []byte("Changes")
+
+// MarkChanged records that the components specified have changed, and whether their place in the hierarchy
+// has changed. A change to the hierarchy is remembered until the changes are cleared. Components are also
+// recorded as changed when they are stored.
+func (bs *boltService) MarkChanged(componentIDs []int64, hierarchy bool) error {
+	return bs.update(func(tx *bolt.Tx) error {
+		return markChanged(tx, componentIDs, hierarchy)
+	})
+}
+
+// markChanged records the changed components within the transaction specified
+func markChanged(tx *bolt.Tx, componentIDs []int64, hierarchy bool) error {
+	if len(componentIDs) == 0 {
+		return nil
+	}
+	bucket, err := tx.CreateBucketIfNotExists(rbkChanges)
+	if err != nil {
+		return err
+	}
+	for _, id := range componentIDs {
+		key := []byte(strconv.FormatInt(id, 10))
+		value := []byte{0}
+		if hierarchy {
+			value[0] = 1
+		} else if existing := bucket.Get(key); existing != nil {
+			continue
+		}
+		if err := bucket.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// movesInHierarchy determines whether replacing the relationship old, which is empty for a new relationship,
+// with relationship r changes the place of its source concept in the hierarchy
+func movesInHierarchy(old *snomed.Relationship, r *snomed.Relationship) bool {
+	wasIsA, isA := old.Active && old.TypeId == snomed.IsA, r.Active && r.TypeId == snomed.IsA
+	return wasIsA != isA || (isA && old.DestinationId != r.DestinationId)
+}
+
+// GetChanged returns the components changed since the changes were last cleared, mapped to whether
+// their place in the hierarchy changed.
+func (bs *boltService) GetChanged() (map[int64]bool, error) {
+	result := make(map[int64]bool)
+	err := bs.view(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(rbkChanges)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			id, err := strconv.ParseInt(string(k), 10, 64)
+			if err != nil {
+				return err
+			}
+			result[id] = len(v) > 0 && v[0] == 1
+			return nil
+		})
+	})
+	return result, err
+}
+
+// ClearChanged forgets all recorded changes, usually once the search index has been updated
+func (bs *boltService) ClearChanged() error {
+	return bs.update(func(tx *bolt.Tx) error {
+		if tx.Bucket(rbkChanges) == nil {
+			return nil
+		}
+		return tx.DeleteBucket(rbkChanges)
+	})
+}
@@ -0,0 +1,431 @@
+// Copyright 2018 Mark Wardle / Eldrix Ltd
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//        http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+//
+
+package boltdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/boltdb/bolt"
+	"github.com/golang/protobuf/proto"
+	"github.com/wardle/go-terminology/snomed"
+	"github.com/wardle/go-terminology/terminology/storage"
+)
+
+// rbkStatistics is a root bucket containing counters, keyed by name, kept up-to-date at write time
+var rbkStatistics = []byte("Statistics")
+
+// Names of counters, or prefixes for counters followed by an identifier
+const (
+	ctrConcepts       = "concepts"
+	ctrDescriptions   = "descriptions"
+	ctrRelationships  = "relationships"
+	ctrRefsetItems    = "refsetitems"
+	ctrActiveSuffix   = ".active"
+	ctrModulePrefix   = "module."
+	ctrRefsetPrefix   = "refset."
+	ctrLanguagePrefix = "language."
+
+	// the size of each top-level hierarchy, derived from the IS-A relationships when first requested
+	// after those relationships change, with ctrHierarchies set once all have been counted
+	ctrHierarchies     = "hierarchies"
+	ctrHierarchyPrefix = "hierarchy."
+)
+
+// counters records changes to the statistics for the store
+type counters map[string]int64
+
+// add counts a component of the specified kind, with delta 1 if added or -1 if removed
+func (c counters) add(kind string, active bool, moduleID int64, delta int64) {
+	c[kind] += delta
+	if active {
+		c[kind+ctrActiveSuffix] += delta
+	}
+	c[ctrModulePrefix+strconv.FormatInt(moduleID, 10)] += delta
+}
+
+func (c counters) addConcept(o *snomed.Concept, delta int64) {
+	c.add(ctrConcepts, o.Active, o.ModuleId, delta)
+}
+
+func (c counters) addDescription(o *snomed.Description, delta int64) {
+	c.add(ctrDescriptions, o.Active, o.ModuleId, delta)
+}
+
+func (c counters) addRelationship(o *snomed.Relationship, delta int64) {
+	c.add(ctrRelationships, o.Active, o.ModuleId, delta)
+}
+
+func (c counters) addReferenceSetItem(o *snomed.ReferenceSetItem, delta int64) {
+	c.add(ctrRefsetItems, o.Active, o.ModuleId, delta)
+	refsetID := strconv.FormatInt(o.RefsetId, 10)
+	c[ctrRefsetPrefix+refsetID] += delta
+	if o.GetLanguage() != nil {
+		c[ctrLanguagePrefix+refsetID] += delta
+	}
+}
+
+// previous reads an existing object, if any, about to be overwritten in the bucket
+// specified, returning whether it was found so that it can be uncounted.
+func previous(bucket *bolt.Bucket, key []byte, o proto.Message) (bool, error) {
+	data := bucket.Get(key)
+	if data == nil {
+		return false, nil
+	}
+	return true, proto.Unmarshal(data, o)
+}
+
+// apply adds the counters to those held in the statistics bucket
+func (c counters) apply(bucket *bolt.Bucket) error {
+	for k, delta := range c {
+		if delta == 0 {
+			continue
+		}
+		var v int64
+		if data := bucket.Get([]byte(k)); data != nil {
+			v, _ = binary.Varint(data)
+		}
+		buf := make([]byte, binary.MaxVarintLen64) // must remain valid for the life of the transaction
+		n := binary.PutVarint(buf, v+delta)
+		if err := bucket.Put([]byte(k), buf[:n]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// statisticsBucket returns the bucket containing statistics, creating it if necessary.
+// A datastore created before statistics were kept at write time is counted in full first.
+func statisticsBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	if bucket := tx.Bucket(rbkStatistics); bucket != nil {
+		return bucket, nil
+	}
+	c, err := recount(tx)
+	if err != nil {
+		return nil, err
+	}
+	bucket, err := tx.CreateBucket(rbkStatistics)
+	if err != nil {
+		return nil, err
+	}
+	return bucket, c.apply(bucket)
+}
+
+// recount counts every component in the store
+func recount(tx *bolt.Tx) (counters, error) {
+	c := make(counters)
+	if bucket := tx.Bucket(rbkConcepts); bucket != nil {
+		err := bucket.ForEach(func(k, v []byte) error {
+			var o snomed.Concept
+			if err := proto.Unmarshal(v, &o); err != nil {
+				return err
+			}
+			c.addConcept(&o, 1)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if bucket := tx.Bucket(rbkDescriptions); bucket != nil {
+		err := bucket.ForEach(func(k, v []byte) error {
+			var o snomed.Description
+			if err := proto.Unmarshal(v, &o); err != nil {
+				return err
+			}
+			c.addDescription(&o, 1)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if propsBucket := tx.Bucket(rbkProperties); propsBucket != nil {
+		err := propsBucket.ForEach(func(k, v []byte) error {
+			conceptBucket := propsBucket.Bucket(k)
+			if conceptBucket == nil || conceptBucket.Bucket(nbkParentRelationships) == nil {
+				return nil
+			}
+			bucket := conceptBucket.Bucket(nbkParentRelationships)
+			return bucket.ForEach(func(k, v []byte) error {
+				var o snomed.Relationship
+				if err := proto.Unmarshal(v, &o); err != nil {
+					return err
+				}
+				c.addRelationship(&o, 1)
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if referenceBucket := tx.Bucket(rbkReferenceSets); referenceBucket != nil {
+		err := referenceBucket.ForEach(func(k, v []byte) error {
+			refsetBucket := referenceBucket.Bucket(k)
+			if refsetBucket == nil {
+				return nil
+			}
+			return refsetBucket.ForEach(func(k, v []byte) error {
+				var o snomed.ReferenceSetItem
+				if err := proto.Unmarshal(v, &o); err != nil {
+					return err
+				}
+				c.addReferenceSetItem(&o, 1)
+				return nil
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// GetStatistics returns statistics for the backend store.
+// Counts are maintained at write time, except for the hierarchies which are derived from the
+// active IS-A relationships when first requested after those relationships change.
+func (bs *boltService) GetStatistics() (storage.Statistics, error) {
+	if !bs.db.IsReadOnly() {
+		if err := bs.updateHierarchies(); err != nil {
+			return storage.Statistics{}, err
+		}
+	}
+	stats := storage.Statistics{
+		Modules:         make(map[int64]int),
+		Hierarchies:     make(map[int64]int),
+		LanguageRefsets: make(map[int64]int),
+		RefsetMembers:   make(map[int64]int),
+		Names:           make(map[int64]string),
+	}
+	err := bs.view(func(tx *bolt.Tx) error {
+		c := make(counters)
+		if bucket := tx.Bucket(rbkStatistics); bucket != nil {
+			err := bucket.ForEach(func(k, v []byte) error {
+				c[string(k)], _ = binary.Varint(v)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		} else {
+			var err error
+			if c, err = recount(tx); err != nil {
+				return err
+			}
+		}
+		for k, v := range c {
+			if v == 0 {
+				continue
+			}
+			if err := setCounter(&stats, k, int(v)); err != nil {
+				return err
+			}
+		}
+		if c[ctrHierarchies] == 0 {
+			if err := bs.cachedHierarchies(tx, stats.Hierarchies); err != nil {
+				return err
+			}
+		}
+		for _, m := range []map[int64]int{stats.Modules, stats.Hierarchies, stats.RefsetMembers} {
+			for id := range m {
+				stats.Names[id] = conceptName(tx, id)
+			}
+		}
+		for id := range stats.RefsetMembers {
+			stats.Refsets = append(stats.Refsets, fmt.Sprintf("%s (%d)", stats.Names[id], id))
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// setCounter sets the statistic corresponding to the named counter
+func setCounter(stats *storage.Statistics, name string, v int) error {
+	switch name {
+	case ctrConcepts:
+		stats.Concepts = v
+	case ctrConcepts + ctrActiveSuffix:
+		stats.ActiveConcepts = v
+	case ctrDescriptions:
+		stats.Descriptions = v
+	case ctrDescriptions + ctrActiveSuffix:
+		stats.ActiveDescriptions = v
+	case ctrRelationships:
+		stats.Relationships = v
+	case ctrRelationships + ctrActiveSuffix:
+		stats.ActiveRelationships = v
+	case ctrRefsetItems:
+		stats.RefsetItems = v
+	case ctrRefsetItems + ctrActiveSuffix:
+		stats.ActiveRefsetItems = v
+	case ctrHierarchies:
+	default:
+		for prefix, m := range map[string]map[int64]int{ctrModulePrefix: stats.Modules, ctrRefsetPrefix: stats.RefsetMembers, ctrLanguagePrefix: stats.LanguageRefsets, ctrHierarchyPrefix: stats.Hierarchies} {
+			if strings.HasPrefix(name, prefix) {
+				id, err := strconv.ParseInt(strings.TrimPrefix(name, prefix), 10, 64)
+				if err != nil {
+					return err
+				}
+				m[id] = v
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown statistic: %s", name)
+	}
+	return nil
+}
+
+// updateHierarchies counts the concepts within each top-level hierarchy, unless already counted since
+// the IS-A relationships last changed, keeping the counts in the statistics bucket
+func (bs *boltService) updateHierarchies() error {
+	counted := false
+	err := bs.view(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket(rbkStatistics); bucket != nil {
+			counted = bucket.Get([]byte(ctrHierarchies)) != nil
+		}
+		return nil
+	})
+	if err != nil || counted {
+		return err
+	}
+	return bs.update(func(tx *bolt.Tx) error {
+		bucket, err := statisticsBucket(tx)
+		if err != nil {
+			return err
+		}
+		hierarchies := make(map[int64]int)
+		if err := countHierarchies(tx, hierarchies); err != nil {
+			return err
+		}
+		c := counters{ctrHierarchies: 1}
+		for id, n := range hierarchies {
+			c[ctrHierarchyPrefix+strconv.FormatInt(id, 10)] = int64(n)
+		}
+		if err := clearHierarchies(bucket); err != nil {
+			return err
+		}
+		return c.apply(bucket)
+	})
+}
+
+// clearHierarchies removes the counts of concepts within each top-level hierarchy, as the IS-A relationships have changed
+func clearHierarchies(bucket *bolt.Bucket) error {
+	keys := [][]byte{[]byte(ctrHierarchies)}
+	prefix := []byte(ctrHierarchyPrefix)
+	c := bucket.Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		keys = append(keys, append([]byte(nil), k...))
+	}
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cachedHierarchies counts the concepts within each top-level hierarchy for a store that cannot keep the counts itself.
+// A read-only store cannot change while open, so the counts are calculated only once.
+func (bs *boltService) cachedHierarchies(tx *bolt.Tx, result map[int64]int) error {
+	if !bs.db.IsReadOnly() {
+		return countHierarchies(tx, result)
+	}
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+	if bs.hierarchies == nil {
+		hierarchies := make(map[int64]int)
+		if err := countHierarchies(tx, hierarchies); err != nil {
+			return err
+		}
+		bs.hierarchies = hierarchies
+	}
+	for id, n := range bs.hierarchies {
+		result[id] = n
+	}
+	return nil
+}
+
+// countHierarchies counts the concepts within each top-level hierarchy, including the top-level concept itself,
+// by following active IS-A relationships from the root concept within a single transaction.
+func countHierarchies(tx *bolt.Tx, result map[int64]int) error {
+	topLevel, err := childConceptIDs(tx, int64(snomed.Root))
+	if err != nil {
+		return err
+	}
+	for _, id := range topLevel {
+		visited := map[int64]bool{id: true}
+		queue := []int64{id}
+		for len(queue) > 0 {
+			children, err := childConceptIDs(tx, queue[0])
+			if err != nil {
+				return err
+			}
+			queue = queue[1:]
+			for _, child := range children {
+				if !visited[child] {
+					visited[child] = true
+					queue = append(queue, child)
+				}
+			}
+		}
+		result[id] = len(visited)
+	}
+	return nil
+}
+
+// childConceptIDs returns the identifiers of the direct children of the concept, using active IS-A relationships
+func childConceptIDs(tx *bolt.Tx, conceptID int64) ([]int64, error) {
+	bucket, err := getPropertiesBucket(tx, conceptID, nbkChildRelationships)
+	if err != nil || bucket == nil {
+		return nil, err
+	}
+	result := make([]int64, 0)
+	err = bucket.ForEach(func(k, v []byte) error {
+		var o snomed.Relationship
+		if err := proto.Unmarshal(v, &o); err != nil {
+			return err
+		}
+		if o.Active && o.TypeId == snomed.IsA {
+			result = append(result, o.SourceId)
+		}
+		return nil
+	})
+	return result, err
+}
+
+// conceptName returns a name for the concept, preferring an active synonym, or an empty string if unknown
+func conceptName(tx *bolt.Tx, conceptID int64) string {
+	bucket, err := getPropertiesBucket(tx, conceptID, nbkDescriptions)
+	if err != nil || bucket == nil {
+		return ""
+	}
+	name := ""
+	bucket.ForEach(func(k, v []byte) error {
+		var o snomed.Description
+		if err := proto.Unmarshal(v, &o); err != nil {
+			return err
+		}
+		if name == "" || (o.Active && o.IsSynonym()) {
+			name = o.Term
+		}
+		return nil
+	})
+	return name
+}
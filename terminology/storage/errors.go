@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned, usually wrapped in an Error, by a storage service.
+var (
+	// ErrNotFound indicates that the requested component does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrWrongComponentType indicates that an identifier or value refers to a different type of component
+	ErrWrongComponentType = errors.New("wrong component type")
+	// ErrStoreClosed indicates that the persistence store has already been closed
+	ErrStoreClosed = errors.New("store closed")
+	// ErrReadOnly indicates that the persistence store was opened read-only and so cannot be changed
+	ErrReadOnly = errors.New("store is read-only")
+)
+
+// Error records a failed operation on a specific component, wrapping one of
+// the sentinel errors above so that callers can determine the cause.
+type Error struct {
+	Kind string // the kind of component requested, e.g. "concept"
+	ID   int64  // the identifier of the component requested, if any
+	Err  error  // the underlying sentinel error
+}
+
+func (e *Error) Error() string {
+	if e.ID == 0 {
+		return fmt.Sprintf("%s: %s", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s %d: %s", e.Kind, e.ID, e.Err)
+}
+
+// Unwrap returns the underlying sentinel error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// IsNotFound returns whether the error, or any error it wraps, indicates a missing component
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsWrongComponentType returns whether the error, or any error it wraps, indicates an unexpected type of component
+func IsWrongComponentType(err error) bool {
+	return errors.Is(err, ErrWrongComponentType)
+}
+
+// IsStoreClosed returns whether the error, or any error it wraps, indicates that the store has been closed
+func IsStoreClosed(err error) bool {
+	return errors.Is(err, ErrStoreClosed)
+}
+
+// IsReadOnly returns whether the error, or any error it wraps, indicates that the store cannot be changed
+func IsReadOnly(err error) bool {
+	return errors.Is(err, ErrReadOnly)
+}
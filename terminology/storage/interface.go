@@ -1,7 +1,10 @@
 package storage
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 
 	"github.com/wardle/go-terminology/snomed"
@@ -17,35 +20,94 @@ type Store interface {
 	GetParentRelationships(concept *snomed.Concept) ([]*snomed.Relationship, error)
 	GetChildRelationships(concept *snomed.Concept) ([]*snomed.Relationship, error)
 	GetAllChildrenIDs(concept *snomed.Concept) ([]int64, error)
+	GetAllChildrenIDsContext(ctx context.Context, concept *snomed.Concept) ([]int64, error)
 	GetReferenceSets(componentID int64) ([]int64, error)
 	GetReferenceSetItems(refset int64) (map[int64]bool, error)
 	GetFromReferenceSet(refset int64, component int64) (*snomed.ReferenceSetItem, error)
 	GetAllReferenceSets() ([]int64, error) // list of installed reference sets
-	Put(components interface{}) error
+	Put(components interface{}) error // store components, recording them as changed
 	Iterate(fn func(*snomed.Concept) error) error
+	IterateContext(ctx context.Context, fn func(*snomed.Concept) error) error
+	IterateDescriptions(ctx context.Context, filter Filter, fn func(*snomed.Description) error) error
+	IterateRelationships(ctx context.Context, filter Filter, fn func(*snomed.Relationship) error) error
+	IterateReferenceSetItems(ctx context.Context, refset int64, filter Filter, fn func(*snomed.ReferenceSetItem) error) error
 	GetStatistics() (Statistics, error)
+	MarkChanged(componentIDs []int64, hierarchy bool) error // record components changed since the search index was updated
+	GetChanged() (map[int64]bool, error)                    // changed components, and whether their place in the hierarchy changed
+	ClearChanged() error
+	Backup(w io.Writer) (int64, error) // write a consistent snapshot of the store
 	Close() error
 }
 
+// Filter restricts the components visited during iteration.
+// The zero value matches all components.
+type Filter struct {
+	ActiveOnly bool  // only active components
+	ModuleID   int64 // only components in this module, if non-zero
+	TypeID     int64 // only descriptions or relationships of this type, if non-zero
+}
+
+// Matches determines whether a component with the given properties passes the filter.
+// The type is ignored for components without a type, such as reference set items.
+func (f Filter) Matches(active bool, moduleID int64, typeID int64) bool {
+	if f.ActiveOnly && !active {
+		return false
+	}
+	if f.ModuleID != 0 && f.ModuleID != moduleID {
+		return false
+	}
+	return f.TypeID == 0 || typeID == 0 || f.TypeID == typeID
+}
+
 // Statistics on the persistence store
 type Statistics struct {
-	Concepts      int
-	Descriptions  int
-	Relationships int
-	RefsetItems   int
-	Refsets       []string
+	Concepts            int
+	Descriptions        int
+	Relationships       int
+	RefsetItems         int
+	Refsets             []string
+	ActiveConcepts      int
+	ActiveDescriptions  int
+	ActiveRelationships int
+	ActiveRefsetItems   int
+	Modules             map[int64]int    // number of components in each module
+	Hierarchies         map[int64]int    // number of concepts within each top-level hierarchy, following active IS-A relationships
+	LanguageRefsets     map[int64]int    // number of items in each language reference set
+	RefsetMembers       map[int64]int    // number of items in each reference set
+	Names               map[int64]string // names for the modules, hierarchies and reference sets above
 }
 
 // String produces formated output of persistence store statistics
 func (st Statistics) String() string {
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("Number of concepts: %d\n", st.Concepts))
-	b.WriteString(fmt.Sprintf("Number of descriptions: %d\n", st.Descriptions))
-	b.WriteString(fmt.Sprintf("Number of relationships: %d\n", st.Relationships))
-	b.WriteString(fmt.Sprintf("Number of reference set items: %d\n", st.RefsetItems))
+	b.WriteString(fmt.Sprintf("Number of concepts: %d (active: %d, inactive: %d)\n", st.Concepts, st.ActiveConcepts, st.Concepts-st.ActiveConcepts))
+	b.WriteString(fmt.Sprintf("Number of descriptions: %d (active: %d, inactive: %d)\n", st.Descriptions, st.ActiveDescriptions, st.Descriptions-st.ActiveDescriptions))
+	b.WriteString(fmt.Sprintf("Number of relationships: %d (active: %d, inactive: %d)\n", st.Relationships, st.ActiveRelationships, st.Relationships-st.ActiveRelationships))
+	b.WriteString(fmt.Sprintf("Number of reference set items: %d (active: %d, inactive: %d)\n", st.RefsetItems, st.ActiveRefsetItems, st.RefsetItems-st.ActiveRefsetItems))
+	st.writeCounts(&b, "Components by module", st.Modules)
+	st.writeCounts(&b, "Concepts by top-level hierarchy", st.Hierarchies)
+	st.writeCounts(&b, "Items by language reference set", st.LanguageRefsets)
 	b.WriteString(fmt.Sprintf("Number of installed refsets: %d:\n", len(st.Refsets)))
-	for _, s := range st.Refsets {
-		b.WriteString(fmt.Sprintf("  Installed refset: %s\n", s))
+	for _, id := range sortedKeys(st.RefsetMembers) {
+		b.WriteString(fmt.Sprintf("  Installed refset: %s (%d): %d items\n", st.Names[id], id, st.RefsetMembers[id]))
 	}
 	return b.String()
 }
+
+// writeCounts writes a titled list of named counts, in identifier order
+func (st Statistics) writeCounts(b *strings.Builder, title string, counts map[int64]int) {
+	b.WriteString(fmt.Sprintf("%s:\n", title))
+	for _, id := range sortedKeys(counts) {
+		b.WriteString(fmt.Sprintf("  %s (%d): %d\n", st.Names[id], id, counts[id]))
+	}
+}
+
+// sortedKeys returns the keys of the map in ascending order
+func sortedKeys(m map[int64]int) []int64 {
+	keys := make([]int64, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
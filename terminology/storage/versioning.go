@@ -12,6 +12,7 @@ import (
 type Descriptor struct {
 	Version   float32
 	StoreType string
+	Index     string `json:",omitempty"` // name of the current search index directory, if rebuilt
 	path      string
 }
 
@@ -37,12 +38,27 @@ func CreateOrOpenDescriptor(path string, currentVersion float32, storeType strin
 	return &desc, json.Unmarshal(data, &desc)
 }
 
-// Save writes the Descriptor to the filesystem
+// ReadDescriptor reads an existing Descriptor from the specified path
+func ReadDescriptor(path string) (*Descriptor, error) {
+	data, err := ioutil.ReadFile(filepath.Join(path, descriptorName))
+	if err != nil {
+		return nil, err
+	}
+	desc := Descriptor{path: path}
+	return &desc, json.Unmarshal(data, &desc)
+}
+
+// Save writes the Descriptor to the filesystem, replacing any existing file
+// atomically so that concurrent readers never see a partially written descriptor.
 func (d *Descriptor) Save() error {
 	descriptorFilename := filepath.Join(d.path, descriptorName)
 	data, err := json.Marshal(d)
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(descriptorFilename, data, 0644)
+	tmp := descriptorFilename + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, descriptorFilename)
 }
@@ -0,0 +1,55 @@
+// Package terminologytest provides a terminology service populated from a tiny SNOMED-CT release,
+// for the tests of packages that need a datastore.
+package terminologytest
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/wardle/go-terminology/terminology"
+)
+
+// Service is a terminology service populated from the tiny release in terminology/testdata,
+// held in a temporary directory that is removed when the service is closed.
+type Service struct {
+	*terminology.Svc
+	Path string // the location of the datastore
+}
+
+// New imports the tiny release into a new datastore, using the options specified, and fails the
+// test if the datastore cannot be created or the release cannot be imported.
+func New(t testing.TB, options ...terminology.Options) *Service {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "terminology-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "snomed.db")
+	svc, err := terminology.New(path, false, options...)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	s := &Service{Svc: svc, Path: path}
+	if err := svc.PerformImport(Release()); err != nil {
+		s.Close()
+		t.Fatalf("could not import test release: %v", err)
+	}
+	return s
+}
+
+// Close closes the service and removes its datastore
+func (s *Service) Close() error {
+	err := s.Svc.Close()
+	os.RemoveAll(filepath.Dir(s.Path))
+	return err
+}
+
+// Release returns the location of the tiny release used for tests
+func Release() string {
+	_, filename, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(filename), "..", "testdata", "release")
+}